@@ -0,0 +1,199 @@
+package darkstar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+)
+
+// AuditReport lists the inconsistencies between a set of master server lists.
+// every list of addresses is sorted, and entries with nothing to report are left out
+type AuditReport struct {
+	Masters []string // addresses of the masters audited
+
+	Partial []string            // masters whose list was missing packets, they're left out of Missing
+	Unique  map[string][]string // by master, servers no other master lists. left empty while another master's list is partial
+	Missing map[string][]string // by master, servers other masters list but it doesn't
+	Stale   map[string][]string // by master, listed servers that didn't answer a PingInfoQuery
+
+	MasterIDs   map[uint16][]string // masters sharing the same MasterID
+	CommonNames map[string][]string // masters by CommonName, only when they don't all match
+	MOTDs       map[string][]string // masters by MOTD, only when they don't all match
+}
+
+// Audit compares the lists returned by Masters, sending a PingInfoQuery to every listed server
+// to find stale entries. servers whose query was cancelled by ctx aren't counted as stale.
+// a partial list isn't held against its master or the others, see AuditReport.Partial
+func (q *Query) Audit(ctx context.Context, masters []*query.MasterQuery) *AuditReport {
+	listed := q.dedupeMasterQuery(masters)
+
+	addresses := make([]string, 0, len(listed))
+	for k := range listed {
+		addresses = append(addresses, k)
+	}
+
+	sort.Strings(addresses)
+
+	games := make(map[string]*ServerResult)
+
+	q.withAddresses(addresses).StreamServers(ctx, func(result *ServerResult) {
+		games[result.Address] = result
+	})
+
+	return newAuditReport(masters, games)
+}
+
+// newAuditReport builds the report from the master lists and the results of querying the servers they list
+func newAuditReport(masters []*query.MasterQuery, games map[string]*ServerResult) (output *AuditReport) {
+	output = &AuditReport{
+		Masters:     make([]string, 0, len(masters)),
+		Partial:     make([]string, 0),
+		Unique:      make(map[string][]string),
+		Missing:     make(map[string][]string),
+		Stale:       make(map[string][]string),
+		MasterIDs:   make(map[uint16][]string),
+		CommonNames: make(map[string][]string),
+		MOTDs:       make(map[string][]string),
+	}
+
+	listedBy := make(map[string]int)
+
+	for _, m := range masters {
+		output.Masters = append(output.Masters, m.Address)
+		output.MasterIDs[m.MasterID] = append(output.MasterIDs[m.MasterID], m.Address)
+		output.CommonNames[m.CommonName] = append(output.CommonNames[m.CommonName], m.Address)
+		output.MOTDs[m.MOTD] = append(output.MOTDs[m.MOTD], m.Address)
+
+		if m.Partial {
+			output.Partial = append(output.Partial, m.Address)
+		}
+
+		for k := range m.Servers {
+			listedBy[k]++
+		}
+	}
+
+	for _, m := range masters {
+		// a server only this master lists may be in the missing packets of another master's partial list
+		unique := len(masters) > 1 && (len(output.Partial) == 0 || len(output.Partial) == 1 && m.Partial)
+
+		for k := range m.Servers {
+			if unique && listedBy[k] == 1 {
+				output.Unique[m.Address] = append(output.Unique[m.Address], k)
+			}
+
+			result, ok := games[k]
+			if ok && result.Status != StatusOK && result.Status != StatusCancelled {
+				output.Stale[m.Address] = append(output.Stale[m.Address], k)
+			}
+		}
+
+		// the servers a partial list leaves out may just be in its missing packets
+		if m.Partial {
+			continue
+		}
+
+		for k := range listedBy {
+			if _, ok := m.Servers[k]; !ok {
+				output.Missing[m.Address] = append(output.Missing[m.Address], k)
+			}
+		}
+	}
+
+	for k, v := range output.MasterIDs {
+		if len(v) < 2 {
+			delete(output.MasterIDs, k)
+		}
+	}
+
+	if len(output.CommonNames) < 2 {
+		output.CommonNames = make(map[string][]string)
+	}
+
+	if len(output.MOTDs) < 2 {
+		output.MOTDs = make(map[string][]string)
+	}
+
+	sort.Strings(output.Masters)
+	sort.Strings(output.Partial)
+
+	for _, v := range []map[string][]string{output.Unique, output.Missing, output.Stale, output.CommonNames, output.MOTDs} {
+		for _, addresses := range v {
+			sort.Strings(addresses)
+		}
+	}
+
+	for _, v := range output.MasterIDs {
+		sort.Strings(v)
+	}
+
+	return
+}
+
+// Consistent reports whether the audit found nothing wrong
+func (r *AuditReport) Consistent() bool {
+	return len(r.Partial) == 0 && len(r.Unique) == 0 && len(r.Missing) == 0 && len(r.Stale) == 0 &&
+		len(r.MasterIDs) == 0 && len(r.CommonNames) == 0 && len(r.MOTDs) == 0
+}
+
+func (r *AuditReport) String() string {
+	if r.Consistent() {
+		return fmt.Sprintf("%d masters consistent", len(r.Masters))
+	}
+
+	output := make([]string, 0)
+
+	for _, master := range r.Masters {
+		for _, v := range r.Partial {
+			if v == master {
+				output = append(output, fmt.Sprintf("%s: partial list", master))
+			}
+		}
+
+		if v := r.Unique[master]; len(v) > 0 {
+			output = append(output, fmt.Sprintf("%s: only lists %s", master, strings.Join(v, ", ")))
+		}
+
+		if v := r.Missing[master]; len(v) > 0 {
+			output = append(output, fmt.Sprintf("%s: missing %s", master, strings.Join(v, ", ")))
+		}
+
+		if v := r.Stale[master]; len(v) > 0 {
+			output = append(output, fmt.Sprintf("%s: stale %s", master, strings.Join(v, ", ")))
+		}
+	}
+
+	ids := make([]int, 0, len(r.MasterIDs))
+	for k := range r.MasterIDs {
+		ids = append(ids, int(k))
+	}
+
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		output = append(output, fmt.Sprintf("MasterID %d shared by %s", id, strings.Join(r.MasterIDs[uint16(id)], ", ")))
+	}
+
+	output = append(output, differences("CommonName", r.CommonNames)...)
+	output = append(output, differences("MOTD", r.MOTDs)...)
+
+	return strings.Join(output, "\n")
+}
+
+func differences(field string, values map[string][]string) (output []string) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		output = append(output, fmt.Sprintf("%s %q used by %s", field, k, strings.Join(values[k], ", ")))
+	}
+
+	return
+}
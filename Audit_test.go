@@ -0,0 +1,125 @@
+package darkstar
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+	Masters []*query.MasterQuery
+}
+
+func (t *AuditTestSuite) SetupTest() {
+	t.Masters = make([]*query.MasterQuery, 0)
+}
+
+func (t *AuditTestSuite) TestAudit_Consistent() {
+	addMaster(&t.Masters, "master1.starsiegeplayers.com:29000", "Starsiege", 1, "96.126.117.1:29001")
+	addMaster(&t.Masters, "master2.starsiegeplayers.com:29000", "Starsiege", 2, "96.126.117.1:29001")
+
+	report := newAuditReport(t.Masters, map[string]*ServerResult{
+		"96.126.117.1:29001": {Status: StatusOK},
+	})
+
+	t.Assert().True(report.Consistent())
+	t.Assert().Equal("2 masters consistent", report.String())
+}
+
+func (t *AuditTestSuite) TestAudit_Inconsistent() {
+	addMaster(&t.Masters, "master1.starsiegeplayers.com:29000", "Starsiege", 1, "96.126.117.1:29001", "96.126.117.2:29001", "96.126.117.3:29001")
+	addMaster(&t.Masters, "master2.starsiegeplayers.com:29000", "Starsiege", 1, "96.126.117.1:29001", "96.126.117.2:29001")
+	addMaster(&t.Masters, "master3.starsiegeplayers.com:29000", "Stale Master", 3, "96.126.117.1:29001", "96.126.117.4:29001").MOTD = "Old"
+
+	report := newAuditReport(t.Masters, map[string]*ServerResult{
+		"96.126.117.1:29001": {Status: StatusOK},
+		"96.126.117.2:29001": {Status: StatusOK},
+		"96.126.117.3:29001": {Status: StatusCancelled},
+		"96.126.117.4:29001": {Status: StatusTimeout},
+	})
+
+	t.Assert().False(report.Consistent())
+
+	t.Assert().Equal(map[string][]string{
+		"master1.starsiegeplayers.com:29000": {"96.126.117.3:29001"},
+		"master3.starsiegeplayers.com:29000": {"96.126.117.4:29001"},
+	}, report.Unique)
+
+	t.Assert().Equal(map[string][]string{
+		"master1.starsiegeplayers.com:29000": {"96.126.117.4:29001"},
+		"master2.starsiegeplayers.com:29000": {"96.126.117.3:29001", "96.126.117.4:29001"},
+		"master3.starsiegeplayers.com:29000": {"96.126.117.2:29001", "96.126.117.3:29001"},
+	}, report.Missing)
+
+	// cancelled queries aren't stale
+	t.Assert().Equal(map[string][]string{
+		"master3.starsiegeplayers.com:29000": {"96.126.117.4:29001"},
+	}, report.Stale)
+
+	t.Assert().Equal(map[uint16][]string{
+		1: {"master1.starsiegeplayers.com:29000", "master2.starsiegeplayers.com:29000"},
+	}, report.MasterIDs)
+
+	t.Assert().Len(report.CommonNames, 2)
+	t.Assert().Equal([]string{"master3.starsiegeplayers.com:29000"}, report.CommonNames["Stale Master"])
+	t.Assert().Len(report.MOTDs, 2)
+
+	t.Assert().Contains(report.String(), "master3.starsiegeplayers.com:29000: stale 96.126.117.4:29001")
+	t.Assert().Contains(report.String(), "MasterID 1 shared by master1.starsiegeplayers.com:29000, master2.starsiegeplayers.com:29000")
+	t.Assert().Contains(report.String(), `CommonName "Stale Master" used by master3.starsiegeplayers.com:29000`)
+}
+
+func (t *AuditTestSuite) TestAudit_Partial() {
+	addMaster(&t.Masters, "master1.starsiegeplayers.com:29000", "Starsiege", 1, "96.126.117.1:29001", "96.126.117.2:29001")
+	addMaster(&t.Masters, "master2.starsiegeplayers.com:29000", "Starsiege", 2, "96.126.117.1:29001", "96.126.117.4:29001").Partial = true
+
+	report := newAuditReport(t.Masters, map[string]*ServerResult{})
+
+	t.Assert().False(report.Consistent())
+	t.Assert().Equal([]string{"master2.starsiegeplayers.com:29000"}, report.Partial)
+
+	// 96.126.117.2 may be in the packets master2 is missing, but master2 did list 96.126.117.4
+	t.Assert().Equal(map[string][]string{
+		"master2.starsiegeplayers.com:29000": {"96.126.117.4:29001"},
+	}, report.Unique)
+	t.Assert().Equal(map[string][]string{
+		"master1.starsiegeplayers.com:29000": {"96.126.117.4:29001"},
+	}, report.Missing)
+
+	t.Assert().Contains(report.String(), "master2.starsiegeplayers.com:29000: partial list")
+}
+
+func (t *AuditTestSuite) TestAudit_Query() {
+	conn, err := net.ListenPacket("udp", crawlAddress)
+	if err != nil {
+		t.T().Skipf("%s isn't available: %s", crawlAddress, err)
+	}
+
+	stale := conn.LocalAddr().String()
+	_ = conn.Close()
+
+	game := gametest.NewUnstartedServer(nil)
+	game.Address = crawlAddress
+	game.Start()
+
+	defer game.Close()
+
+	addMaster(&t.Masters, "master1.starsiegeplayers.com:29000", "Starsiege", 1, game.Address, stale)
+
+	report := NewQuery(200*time.Millisecond, false).Audit(context.Background(), t.Masters)
+
+	t.Assert().Equal(map[string][]string{
+		"master1.starsiegeplayers.com:29000": {stale},
+	}, report.Stale)
+	t.Assert().Empty(report.Unique)
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
@@ -0,0 +1,166 @@
+package darkstar
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+)
+
+type CrawlOptions struct {
+	Budget   time.Duration // time allowed for the whole crawl, 0 is only limited by ctx
+	GameInfo bool          // also send a GameInfoQuery to every game server that answered
+}
+
+// Snapshot is the state of the network found by Crawl
+type Snapshot struct {
+	RequestTime time.Time
+	Elapsed     time.Duration
+
+	Masters  []*query.MasterQuery     // every master that answered, including partial lists
+	Games    []*query.PingInfoQuery   // every game server that answered
+	GameInfo []*query.GameInfoQuery   // only filled in with CrawlOptions.GameInfo
+	Listed   map[string]*ListedServer // every game server listed by a master, with the masters listing it
+
+	// outcome of every address queried by each step of the crawl
+	MasterResults   map[string]*ServerResult `json:"-"`
+	GameResults     map[string]*ServerResult `json:"-"`
+	GameInfoResults map[string]*ServerResult `json:"-"`
+	Errors          []error                  `json:"-"`
+}
+
+// Crawl queries every master in Addresses, then every game server they list, then optionally the
+// game info of every server that answered. a step cut short by the Budget or ctx leaves its remaining
+// addresses with StatusCancelled and the steps after it are skipped
+func (q *Query) Crawl(ctx context.Context, options CrawlOptions) (snapshot *Snapshot) {
+	if options.Budget > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, options.Budget)
+		defer cancel()
+	}
+
+	snapshot = &Snapshot{
+		RequestTime:     time.Now(),
+		Masters:         make([]*query.MasterQuery, 0),
+		Games:           make([]*query.PingInfoQuery, 0),
+		GameInfo:        make([]*query.GameInfoQuery, 0),
+		Listed:          make(map[string]*ListedServer),
+		MasterResults:   make(map[string]*ServerResult),
+		GameResults:     make(map[string]*ServerResult),
+		GameInfoResults: make(map[string]*ServerResult),
+		Errors:          make([]error, 0),
+	}
+
+	defer func() {
+		snapshot.Elapsed = time.Since(snapshot.RequestTime)
+	}()
+
+	summary := q.StreamMasters(ctx, func(result *ServerResult) {
+		snapshot.MasterResults[result.Address] = result
+		snapshot.addError(result)
+
+		if result.Master.Ping > 0 {
+			snapshot.Masters = append(snapshot.Masters, result.Master)
+		}
+	})
+
+	snapshot.Listed = summary.Games
+
+	// the queries time out on the budget deadline itself, which can be before ctx.Err() reports it
+	if query.ContextError(ctx) != nil {
+		return
+	}
+
+	games := q.withAddresses(snapshot.listedAddresses())
+	games.StreamServers(ctx, func(result *ServerResult) {
+		snapshot.GameResults[result.Address] = result
+		snapshot.addError(result)
+
+		if result.Error == nil {
+			snapshot.Games = append(snapshot.Games, result.Game)
+		}
+	})
+
+	if !options.GameInfo || query.ContextError(ctx) != nil {
+		return
+	}
+
+	answered := make([]string, 0, len(snapshot.Games))
+	for _, v := range snapshot.Games {
+		answered = append(answered, v.Address)
+	}
+
+	q.withAddresses(answered).streamGameInfo(ctx, func(result *ServerResult) {
+		snapshot.GameInfoResults[result.Address] = result
+		snapshot.addError(result)
+
+		if result.Error == nil {
+			snapshot.GameInfo = append(snapshot.GameInfo, result.GameInfo)
+		}
+	})
+
+	return
+}
+
+func (s *Snapshot) addError(result *ServerResult) {
+	if result.Error != nil {
+		s.Errors = append(s.Errors, result.Error)
+	}
+}
+
+// listedAddresses returns every game server listed by any master, sorted
+func (s *Snapshot) listedAddresses() (output []string) {
+	output = make([]string, 0, len(s.Listed))
+
+	for k := range s.Listed {
+		output = append(output, k)
+	}
+
+	sort.Strings(output)
+
+	return
+}
+
+// withAddresses returns a copy of the query for a different set of addresses, sharing Options and Conn
+func (q *Query) withAddresses(addresses []string) *Query {
+	return &Query{
+		Options:   q.Options,
+		Addresses: addresses,
+		Conn:      q.Conn,
+	}
+}
+
+func (q *Query) streamGameInfo(ctx context.Context, callback func(result *ServerResult)) (summary *Summary) {
+	summary = newSummary(len(q.Addresses))
+
+	await := q.fanOut(ctx, q.Addresses, func(address string) *ServerResult {
+		return q.performGameInfoQuery(ctx, address)
+	})
+
+	for i := 0; i < summary.Total; i++ {
+		result := <-await
+		summary.add(result)
+
+		callback(result)
+	}
+
+	close(await)
+
+	summary.finish()
+
+	return
+}
+
+func (q *Query) performGameInfoQuery(ctx context.Context, address string) *ServerResult {
+	r := &ServerResult{
+		Address: address,
+		Started: time.Now(),
+	}
+
+	r.GameInfo = query.NewGameInfoQueryWithOptions(address, q.Options)
+	r.finish(r.GameInfo.QueryContext(ctx), r.GameInfo.Attempts())
+
+	return r
+}
@@ -0,0 +1,142 @@
+package darkstar
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/mastertest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/server"
+	"github.com/stretchr/testify/suite"
+)
+
+// crawlAddress is where the game servers listen, masters don't list servers on 127.0.0.1
+const crawlAddress = "127.0.0.2:0"
+
+type CrawlTestSuite struct {
+	suite.Suite
+	Games   []*gametest.Server
+	Masters []*mastertest.Server
+	Query   *Query
+}
+
+func (t *CrawlTestSuite) SetupTest() {
+	conn, err := net.ListenPacket("udp", crawlAddress)
+	if err != nil {
+		t.T().Skipf("%s isn't available: %s", crawlAddress, err)
+	}
+
+	_ = conn.Close()
+
+	t.Games = make([]*gametest.Server, 0)
+	t.Masters = make([]*mastertest.Server, 0)
+	t.Query = NewQuery(200*time.Millisecond, false)
+
+	for _, name := range []string{"alpha", "bravo", "charlie"} {
+		info := gametest.DefaultPingInfo()
+		info.Name = []byte(name)
+
+		s := gametest.NewUnstartedServer(info)
+		s.Address = crawlAddress
+		s.Start()
+
+		s.SetGameInfo(&protocol.GameInfo{
+			GameName: "es3a",
+			Name:     name,
+		})
+
+		t.Games = append(t.Games, s)
+	}
+}
+
+func (t *CrawlTestSuite) TearDownTest() {
+	for _, v := range t.Games {
+		v.Close()
+	}
+
+	for _, v := range t.Masters {
+		v.Close()
+	}
+}
+
+func (t *CrawlTestSuite) newMaster(name string, games ...*gametest.Server) *mastertest.Server {
+	master := protocol.NewMaster()
+	master.CommonName = name
+
+	for _, v := range games {
+		master.Servers[v.Address], _ = server.NewServerFromString(v.Address)
+	}
+
+	s := mastertest.NewServer(master)
+
+	t.Masters = append(t.Masters, s)
+	t.Query.Addresses = append(t.Query.Addresses, s.Address)
+
+	return s
+}
+
+func (t *CrawlTestSuite) TestCrawl() {
+	alpha := t.newMaster("alpha", t.Games[0], t.Games[1])
+	bravo := t.newMaster("bravo", t.Games[1], t.Games[2])
+	t.newMaster("dropped").SetFaults(mastertest.Faults{Drop: 1})
+
+	t.Games[2].SetFaults(gametest.Faults{Drop: 1})
+
+	snapshot := t.Query.Crawl(context.Background(), CrawlOptions{})
+
+	t.Assert().Len(snapshot.Masters, 2)
+	t.Assert().Len(snapshot.MasterResults, 3)
+	t.Assert().Len(snapshot.Games, 2)
+	t.Assert().Len(snapshot.GameResults, 3)
+	t.Assert().Equal(StatusTimeout, snapshot.GameResults[t.Games[2].Address].Status)
+	t.Assert().Empty(snapshot.GameInfo)
+	t.Assert().Empty(snapshot.GameInfoResults)
+	t.Assert().Len(snapshot.Errors, 2)
+
+	t.Assert().Equal([]string{alpha.Address}, snapshot.Listed[t.Games[0].Address].MasterAddresses())
+	t.Assert().ElementsMatch([]string{alpha.Address, bravo.Address}, snapshot.Listed[t.Games[1].Address].MasterAddresses())
+	t.Assert().Equal([]string{bravo.Address}, snapshot.Listed[t.Games[2].Address].MasterAddresses())
+
+	t.Assert().False(snapshot.RequestTime.IsZero())
+	t.Assert().Greater(int64(snapshot.Elapsed), int64(0))
+}
+
+func (t *CrawlTestSuite) TestCrawl_GameInfo() {
+	t.newMaster("alpha", t.Games...)
+
+	snapshot := t.Query.Crawl(context.Background(), CrawlOptions{GameInfo: true})
+
+	t.Assert().Empty(snapshot.Errors)
+	t.Assert().Len(snapshot.Games, 3)
+	t.Require().Len(snapshot.GameInfo, 3)
+	t.Assert().Equal("es3a", snapshot.GameInfo[0].GameName)
+	t.Assert().Equal(StatusOK, snapshot.GameInfoResults[t.Games[0].Address].Status)
+}
+
+func (t *CrawlTestSuite) TestCrawl_Budget() {
+	t.Query.Timeout = 5 * time.Second
+
+	t.newMaster("alpha", t.Games...)
+	t.Games[0].SetFaults(gametest.Faults{Delay: time.Second})
+
+	start := time.Now()
+
+	snapshot := t.Query.Crawl(context.Background(), CrawlOptions{
+		Budget:   200 * time.Millisecond,
+		GameInfo: true,
+	})
+
+	t.Assert().Less(int64(time.Since(start)), int64(time.Second))
+	t.Assert().Len(snapshot.Games, 2)
+	t.Assert().Equal(StatusCancelled, snapshot.GameResults[t.Games[0].Address].Status)
+
+	// the game info step is skipped once the budget runs out
+	t.Assert().Empty(snapshot.GameInfoResults)
+}
+
+func TestCrawlTestSuite(t *testing.T) {
+	suite.Run(t, new(CrawlTestSuite))
+}
@@ -0,0 +1,61 @@
+package darkstar
+
+import (
+	"context"
+	"time"
+)
+
+// fanOut calls perform for every address in its own goroutine, keeping to MaxInFlight and PacketsPerSecond.
+// once ctx is done the limits are ignored so the remaining queries can fail fast with ctx.Err()
+func (q *Query) fanOut(ctx context.Context, addresses []string, perform func(address string) *ServerResult) chan *ServerResult {
+	results := make(chan *ServerResult)
+
+	var inFlight chan struct{}
+	if q.MaxInFlight > 0 {
+		inFlight = make(chan struct{}, q.MaxInFlight)
+	}
+
+	var interval time.Duration
+	if q.PacketsPerSecond > 0 {
+		interval = time.Second / time.Duration(q.PacketsPerSecond)
+	}
+
+	go func() {
+		var ticker *time.Ticker
+		if interval > 0 {
+			ticker = time.NewTicker(interval)
+			defer ticker.Stop()
+		}
+
+		for i, address := range addresses {
+			acquired := false
+
+			if inFlight != nil {
+				select {
+				case inFlight <- struct{}{}:
+					acquired = true
+				case <-ctx.Done():
+				}
+			}
+
+			if ticker != nil && i > 0 {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+				}
+			}
+
+			go func(address string, acquired bool) {
+				r := perform(address)
+
+				if acquired {
+					<-inFlight
+				}
+
+				results <- r
+			}(address, acquired)
+		}
+	}()
+
+	return results
+}
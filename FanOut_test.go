@@ -0,0 +1,98 @@
+package darkstar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FanOutTestSuite struct {
+	suite.Suite
+	Query *Query
+}
+
+func (t *FanOutTestSuite) SetupTest() {
+	t.Query = NewQuery(time.Second, false)
+
+	for i := 0; i < 10; i++ {
+		t.Query.Addresses = append(t.Query.Addresses, fmt.Sprintf("96.126.117.%d:29001", i+1))
+	}
+}
+
+// run fans out over every address, each perform taking d, and returns the most that ran at once
+func (t *FanOutTestSuite) run(ctx context.Context, d time.Duration) (maximum int) {
+	var lock sync.Mutex
+
+	current := 0
+	seen := make(map[string]bool)
+
+	await := t.Query.fanOut(ctx, t.Query.Addresses, func(address string) *ServerResult {
+		lock.Lock()
+		current++
+		if current > maximum {
+			maximum = current
+		}
+		seen[address] = true
+		lock.Unlock()
+
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+		}
+
+		lock.Lock()
+		current--
+		lock.Unlock()
+
+		return new(ServerResult)
+	})
+
+	for range t.Query.Addresses {
+		<-await
+	}
+
+	t.Assert().Len(seen, len(t.Query.Addresses))
+
+	return
+}
+
+func (t *FanOutTestSuite) TestFanOut_Unlimited() {
+	t.Assert().Equal(10, t.run(context.Background(), 50*time.Millisecond))
+}
+
+func (t *FanOutTestSuite) TestFanOut_MaxInFlight() {
+	t.Query.MaxInFlight = 3
+
+	t.Assert().LessOrEqual(t.run(context.Background(), 10*time.Millisecond), 3)
+}
+
+func (t *FanOutTestSuite) TestFanOut_PacketsPerSecond() {
+	t.Query.PacketsPerSecond = 100
+
+	start := time.Now()
+	t.run(context.Background(), 0)
+
+	// nine intervals between the ten queries
+	t.Assert().GreaterOrEqual(int64(time.Since(start)), int64(90*time.Millisecond))
+}
+
+func (t *FanOutTestSuite) TestFanOut_Cancelled() {
+	t.Query.MaxInFlight = 1
+	t.Query.PacketsPerSecond = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	t.run(ctx, time.Hour)
+
+	t.Assert().Less(int64(time.Since(start)), int64(500*time.Millisecond))
+}
+
+func TestFanOutTestSuite(t *testing.T) {
+	suite.Run(t, new(FanOutTestSuite))
+}
@@ -0,0 +1,161 @@
+package darkstar
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+)
+
+// Filter reports whether a game server should be kept
+type Filter func(game *query.PingInfoQuery) bool
+
+// FilterGames returns the games every filter keeps, in their original order
+func FilterGames(games []*query.PingInfoQuery, filters ...Filter) (output []*query.PingInfoQuery) {
+	output = make([]*query.PingInfoQuery, 0, len(games))
+	keep := And(filters...)
+
+	for _, v := range games {
+		if keep(v) {
+			output = append(output, v)
+		}
+	}
+
+	return
+}
+
+// And keeps games every filter keeps, no filters keeps every game
+func And(filters ...Filter) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		for _, f := range filters {
+			if !f(game) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or keeps games any filter keeps, no filters keeps no game
+func Or(filters ...Filter) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		for _, f := range filters {
+			if f(game) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func Not(filter Filter) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return !filter(game)
+	}
+}
+
+// HasStatus keeps games with bit set in GameStatus
+func HasStatus(bit protocol.StatusBit) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return game.GameStatus.Has(bit)
+	}
+}
+
+// PlayersBetween keeps games with min <= PlayerCount <= max
+func PlayersBetween(min int, max int) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return int(game.PlayerCount) >= min && int(game.PlayerCount) <= max
+	}
+}
+
+func NotFull() Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return game.PlayerCount < game.MaxPlayers
+	}
+}
+
+func NotEmpty() Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return game.PlayerCount > 0
+	}
+}
+
+// GameName keeps games reporting name, es3a for Starsiege
+func GameName(name string) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return trimNull(game.GameName) == name
+	}
+}
+
+// GameVersion keeps games reporting version, such as "V 001.004r"
+func GameVersion(version string) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return trimNull(game.GameVersion) == version
+	}
+}
+
+// NameContains keeps games whose name contains substring, ignoring case
+func NameContains(substring string) Filter {
+	substring = strings.ToLower(substring)
+
+	return func(game *query.PingInfoQuery) bool {
+		return strings.Contains(strings.ToLower(string(game.Name)), substring)
+	}
+}
+
+func NameMatches(expression *regexp.Regexp) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return expression.Match(game.Name)
+	}
+}
+
+// PingBelow keeps games that answered within ceiling
+func PingBelow(ceiling time.Duration) Filter {
+	return func(game *query.PingInfoQuery) bool {
+		return game.Ping <= ceiling
+	}
+}
+
+// the fixed size fields of a PingInfoResponse are padded with nulls
+func trimNull(data []byte) string {
+	return strings.TrimRight(string(data), "\x00")
+}
+
+// Order reports whether a sorts before b
+type Order func(a *query.PingInfoQuery, b *query.PingInfoQuery) bool
+
+var (
+	ByPing    Order = func(a *query.PingInfoQuery, b *query.PingInfoQuery) bool { return a.Ping < b.Ping }
+	ByPlayers Order = func(a *query.PingInfoQuery, b *query.PingInfoQuery) bool { return a.PlayerCount < b.PlayerCount }
+	ByName    Order = func(a *query.PingInfoQuery, b *query.PingInfoQuery) bool {
+		return strings.ToLower(string(a.Name)) < strings.ToLower(string(b.Name))
+	}
+)
+
+func Reverse(order Order) Order {
+	return func(a *query.PingInfoQuery, b *query.PingInfoQuery) bool {
+		return order(b, a)
+	}
+}
+
+// SortGames sorts games in place by the first order, using the orders after it to break ties.
+// games every order considers equal keep their original order
+func SortGames(games []*query.PingInfoQuery, orders ...Order) {
+	sort.SliceStable(games, func(i, j int) bool {
+		for _, less := range orders {
+			switch {
+			case less(games[i], games[j]):
+				return true
+			case less(games[j], games[i]):
+				return false
+			}
+		}
+
+		return false
+	})
+}
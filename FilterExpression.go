@@ -0,0 +1,398 @@
+package darkstar
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+)
+
+var ErrorFilterSyntax = errors.New("invalid filter expression")
+
+// flags are the bare words of a filter expression
+var flags = map[string]Filter{
+	"protected":       HasStatus(protocol.Protected),
+	"dedicated":       HasStatus(protocol.Dedicated),
+	"allowoldclients": HasStatus(protocol.AllowOldClients),
+	"started":         HasStatus(protocol.Started),
+	"full":            Not(NotFull()),
+	"empty":           Not(NotEmpty()),
+}
+
+// numberFields are compared with == != < <= > >=
+var numberFields = map[string]func(game *query.PingInfoQuery) int64{
+	"players":    func(game *query.PingInfoQuery) int64 { return int64(game.PlayerCount) },
+	"maxplayers": func(game *query.PingInfoQuery) int64 { return int64(game.MaxPlayers) },
+	"ping":       func(game *query.PingInfoQuery) int64 { return int64(game.Ping) },
+}
+
+// stringFields are compared with == != and ~ for a regular expression match
+var stringFields = map[string]func(game *query.PingInfoQuery) string{
+	"name":    func(game *query.PingInfoQuery) string { return string(game.Name) },
+	"game":    func(game *query.PingInfoQuery) string { return trimNull(game.GameName) },
+	"version": func(game *query.PingInfoQuery) string { return trimNull(game.GameVersion) },
+}
+
+var orders = map[string]Order{
+	"ping":    ByPing,
+	"players": ByPlayers,
+	"name":    ByName,
+}
+
+// ParseFilter parses expressions such as `players>0 && !protected`.
+// flags: protected, dedicated, allowoldclients, started, full and empty.
+// numbers: players, maxplayers and ping, which takes a duration such as 150ms or a number of milliseconds.
+// strings: name, game and version, quoted when they hold spaces or operators. == on name ignores case
+// and ~ matches a regular expression. terms combine with !, &&, || and parentheses.
+// an empty expression keeps every game
+func ParseFilter(expression string) (Filter, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return And(), nil
+	}
+
+	p := &filterParser{tokens: tokens}
+
+	filter, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.more() {
+		return nil, p.unexpected()
+	}
+
+	return filter, nil
+}
+
+// ParseOrder parses a comma separated list of ping, players and name, each reversed by a leading -
+func ParseOrder(expression string) (output []Order, err error) {
+	output = make([]Order, 0)
+
+	for _, v := range strings.Split(expression, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" {
+			continue
+		}
+
+		reverse := strings.HasPrefix(v, "-")
+
+		order, ok := orders[strings.TrimPrefix(v, "-")]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown order %q", ErrorFilterSyntax, v)
+		}
+
+		if reverse {
+			order = Reverse(order)
+		}
+
+		output = append(output, order)
+	}
+
+	return
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// operators longest first so <= isn't read as <
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "(", ")", "!", "<", ">", "=", "~"}
+
+func tokenize(expression string) (output []token, err error) {
+	output = make([]token, 0)
+
+	for i := 0; i < len(expression); {
+		switch c := expression[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"':
+			end := i + 1
+			for end < len(expression) && expression[end] != '"' {
+				if expression[end] == '\\' {
+					end++
+				}
+				end++
+			}
+
+			if end >= len(expression) {
+				return nil, fmt.Errorf("%w: unterminated string at %d", ErrorFilterSyntax, i)
+			}
+
+			text, err := strconv.Unquote(expression[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad string at %d: %s", ErrorFilterSyntax, i, err)
+			}
+
+			output = append(output, token{kind: tokenString, text: text, position: i})
+			i = end + 1
+
+		default:
+			if operator := operatorAt(expression, i); operator != "" {
+				output = append(output, token{kind: tokenOperator, text: operator, position: i})
+				i += len(operator)
+
+				// = is accepted as ==
+				if operator == "=" {
+					output[len(output)-1].text = "=="
+				}
+
+				continue
+			}
+
+			end := i
+			for end < len(expression) && !strings.ContainsRune(" \t\n\r\"()!&|=<>~", rune(expression[end])) {
+				end++
+			}
+
+			if end == i {
+				return nil, fmt.Errorf("%w: unexpected %q at %d", ErrorFilterSyntax, expression[i], i)
+			}
+
+			output = append(output, token{kind: tokenWord, text: expression[i:end], position: i})
+			i = end
+		}
+	}
+
+	return
+}
+
+func operatorAt(expression string, i int) string {
+	for _, v := range operators {
+		if strings.HasPrefix(expression[i:], v) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// filterParser is a recursive descent parser, || binds loosest then && then !
+type filterParser struct {
+	tokens []token
+	next   int
+}
+
+func (p *filterParser) more() bool {
+	return p.next < len(p.tokens)
+}
+
+func (p *filterParser) peek(operator string) bool {
+	return p.more() && p.tokens[p.next].kind == tokenOperator && p.tokens[p.next].text == operator
+}
+
+// comparison reports whether the next token compares a field with a value
+func (p *filterParser) comparison() bool {
+	for _, v := range []string{"==", "!=", "<", "<=", ">", ">=", "~"} {
+		if p.peek(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *filterParser) unexpected() error {
+	if !p.more() {
+		return fmt.Errorf("%w: unexpected end of expression", ErrorFilterSyntax)
+	}
+
+	t := p.tokens[p.next]
+
+	return fmt.Errorf("%w: unexpected %q at %d", ErrorFilterSyntax, t.text, t.position)
+}
+
+func (p *filterParser) or() (Filter, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek("||") {
+		p.next++
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		left = Or(left, right)
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) and() (Filter, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek("&&") {
+		p.next++
+
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = And(left, right)
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) unary() (Filter, error) {
+	switch {
+	case p.peek("!"):
+		p.next++
+
+		filter, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return Not(filter), nil
+
+	case p.peek("("):
+		p.next++
+
+		filter, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.peek(")") {
+			return nil, p.unexpected()
+		}
+
+		p.next++
+
+		return filter, nil
+
+	case !p.more() || p.tokens[p.next].kind != tokenWord:
+		return nil, p.unexpected()
+	}
+
+	field := p.tokens[p.next]
+	name := strings.ToLower(field.text)
+	p.next++
+
+	if !p.comparison() {
+		filter, ok := flags[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown flag %q at %d", ErrorFilterSyntax, field.text, field.position)
+		}
+
+		return filter, nil
+	}
+
+	operator := p.tokens[p.next]
+	p.next++
+
+	if !p.more() || p.tokens[p.next].kind == tokenOperator {
+		return nil, p.unexpected()
+	}
+
+	value := p.tokens[p.next]
+	p.next++
+
+	if get, ok := numberFields[name]; ok {
+		return numberComparison(get, name, operator, value)
+	}
+
+	if get, ok := stringFields[name]; ok {
+		return stringComparison(get, name, operator, value)
+	}
+
+	return nil, fmt.Errorf("%w: unknown field %q at %d", ErrorFilterSyntax, field.text, field.position)
+}
+
+func numberComparison(get func(game *query.PingInfoQuery) int64, name string, operator token, value token) (Filter, error) {
+	var (
+		want int64
+		err  error
+	)
+
+	if name == "ping" {
+		var d time.Duration
+
+		d, err = time.ParseDuration(value.text)
+		if err != nil {
+			var ms int64
+
+			ms, err = strconv.ParseInt(value.text, 10, 64)
+			d = time.Duration(ms) * time.Millisecond
+		}
+
+		want = int64(d)
+	} else {
+		want, err = strconv.ParseInt(value.text, 10, 64)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad %s %q at %d", ErrorFilterSyntax, name, value.text, value.position)
+	}
+
+	compare := map[string]func(have int64) bool{
+		"==": func(have int64) bool { return have == want },
+		"!=": func(have int64) bool { return have != want },
+		"<":  func(have int64) bool { return have < want },
+		"<=": func(have int64) bool { return have <= want },
+		">":  func(have int64) bool { return have > want },
+		">=": func(have int64) bool { return have >= want },
+	}[operator.text]
+
+	if compare == nil {
+		return nil, fmt.Errorf("%w: %s can't be compared with %q at %d", ErrorFilterSyntax, name, operator.text, operator.position)
+	}
+
+	return func(game *query.PingInfoQuery) bool {
+		return compare(get(game))
+	}, nil
+}
+
+func stringComparison(get func(game *query.PingInfoQuery) string, name string, operator token, value token) (Filter, error) {
+	equal := func(have string) bool { return have == value.text }
+	if name == "name" {
+		equal = func(have string) bool { return strings.EqualFold(have, value.text) }
+	}
+
+	switch operator.text {
+	case "==":
+		return func(game *query.PingInfoQuery) bool { return equal(get(game)) }, nil
+
+	case "!=":
+		return func(game *query.PingInfoQuery) bool { return !equal(get(game)) }, nil
+
+	case "~":
+		expression, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad regular expression at %d: %s", ErrorFilterSyntax, value.position, err)
+		}
+
+		return func(game *query.PingInfoQuery) bool { return expression.MatchString(get(game)) }, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s can't be compared with %q at %d", ErrorFilterSyntax, name, operator.text, operator.position)
+}
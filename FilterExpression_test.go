@@ -0,0 +1,89 @@
+package darkstar
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/stretchr/testify/suite"
+)
+
+type FilterExpressionTestSuite struct {
+	suite.Suite
+	Games []*query.PingInfoQuery
+}
+
+func (t *FilterExpressionTestSuite) SetupTest() {
+	t.Games = []*query.PingInfoQuery{
+		newGame("Alpha", 0, protocol.Dedicated, 80*time.Millisecond),
+		newGame("bravo", 8, protocol.Dedicated|protocol.Started, 20*time.Millisecond),
+		newGame("Charlie Base", 3, protocol.Protected|protocol.Started, 150*time.Millisecond),
+		newGame("delta", 3, protocol.AllowOldClients, 20*time.Millisecond),
+	}
+
+	t.Games[3].GameVersion = []byte("V 001.003r")
+}
+
+func (t *FilterExpressionTestSuite) filter(expression string) []string {
+	filter, err := ParseFilter(expression)
+	t.Require().Nil(err, expression)
+
+	return gameNames(FilterGames(t.Games, filter))
+}
+
+func (t *FilterExpressionTestSuite) TestParseFilter() {
+	t.Assert().Equal([]string{"Alpha", "bravo", "Charlie Base", "delta"}, t.filter(""))
+	t.Assert().Equal([]string{"bravo", "delta"}, t.filter("players>0 && !protected"))
+	t.Assert().Equal([]string{"Alpha", "bravo"}, t.filter("dedicated"))
+	t.Assert().Equal([]string{"Charlie Base", "delta"}, t.filter("!full && !empty"))
+	t.Assert().Equal([]string{"Alpha", "Charlie Base"}, t.filter("empty || protected"))
+	t.Assert().Equal([]string{"Alpha", "delta"}, t.filter("!started && (empty || allowoldclients)"))
+	t.Assert().Equal([]string{"Charlie Base", "delta"}, t.filter("players == 3"))
+	t.Assert().Equal([]string{"Charlie Base", "delta"}, t.filter("players>=1 && players<8"))
+	t.Assert().Equal([]string{"Alpha", "bravo", "Charlie Base", "delta"}, t.filter("maxplayers = 8"))
+	t.Assert().Equal([]string{"bravo", "delta"}, t.filter("ping < 50ms"))
+	t.Assert().Equal([]string{"Alpha", "bravo", "delta"}, t.filter("ping <= 80"))
+	t.Assert().Equal([]string{"bravo"}, t.filter("name == BRAVO"))
+	t.Assert().Equal([]string{"Charlie Base"}, t.filter(`name == "charlie base"`))
+	t.Assert().Equal([]string{"Charlie Base"}, t.filter(`name ~ "(?i)base$"`))
+	t.Assert().Equal([]string{"Alpha", "bravo", "Charlie Base"}, t.filter(`version != "V 001.003r"`))
+	t.Assert().Equal([]string{"Alpha", "bravo", "Charlie Base", "delta"}, t.filter("game==es3a"))
+}
+
+func (t *FilterExpressionTestSuite) TestParseFilter_Errors() {
+	for _, expression := range []string{
+		"players >",
+		"players > many",
+		"players ~ 3",
+		"name < bravo",
+		"name ~ \"(\"",
+		"crowded",
+		"colour == red",
+		"(started",
+		"started)",
+		"started protected",
+		"started & protected",
+		"name == \"bravo",
+		"&& started",
+	} {
+		_, err := ParseFilter(expression)
+		t.Assert().True(errors.Is(err, ErrorFilterSyntax), expression)
+	}
+}
+
+func (t *FilterExpressionTestSuite) TestParseOrder() {
+	orders, err := ParseOrder("-players, ping")
+	t.Require().Nil(err)
+
+	SortGames(t.Games, orders...)
+	t.Assert().Equal([]string{"bravo", "delta", "Charlie Base", "Alpha"}, gameNames(t.Games))
+
+	_, err = ParseOrder("ping,address")
+	t.Assert().True(errors.Is(err, ErrorFilterSyntax))
+}
+
+func TestFilterExpressionTestSuite(t *testing.T) {
+	suite.Run(t, new(FilterExpressionTestSuite))
+}
@@ -0,0 +1,88 @@
+package darkstar
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/stretchr/testify/suite"
+)
+
+type FilterTestSuite struct {
+	suite.Suite
+	Games []*query.PingInfoQuery
+}
+
+func newGame(name string, players byte, status protocol.StatusBit, ping time.Duration) *query.PingInfoQuery {
+	game := query.NewPingInfoQuery(name + ":29001")
+	game.Name = []byte(name)
+	game.GameName = []byte("es3a")
+	game.GameVersion = []byte("V 001.004r")
+	game.PlayerCount = players
+	game.MaxPlayers = 8
+	game.GameStatus = protocol.StatusByte(status)
+	game.Ping = ping
+
+	return game
+}
+
+func (t *FilterTestSuite) SetupTest() {
+	t.Games = []*query.PingInfoQuery{
+		newGame("Alpha", 0, protocol.Dedicated, 80*time.Millisecond),
+		newGame("bravo", 8, protocol.Dedicated|protocol.Started, 20*time.Millisecond),
+		newGame("Charlie", 3, protocol.Protected|protocol.Started, 150*time.Millisecond),
+		newGame("delta", 3, protocol.AllowOldClients, 20*time.Millisecond),
+	}
+
+	t.Games[3].GameVersion = []byte("V 001.003r")
+}
+
+// gameNames is names without sorting
+func gameNames(games []*query.PingInfoQuery) (output []string) {
+	output = make([]string, 0, len(games))
+
+	for _, v := range games {
+		output = append(output, string(v.Name))
+	}
+
+	return
+}
+
+func (t *FilterTestSuite) TestFilterGames() {
+	t.Assert().Equal([]string{"Alpha", "bravo", "Charlie", "delta"}, gameNames(FilterGames(t.Games)))
+	t.Assert().Equal([]string{"Alpha", "bravo"}, gameNames(FilterGames(t.Games, HasStatus(protocol.Dedicated))))
+	t.Assert().Equal([]string{"Charlie"}, gameNames(FilterGames(t.Games, HasStatus(protocol.Started), NotFull())))
+	t.Assert().Equal([]string{"bravo", "Charlie", "delta"}, gameNames(FilterGames(t.Games, NotEmpty())))
+	t.Assert().Equal([]string{"Charlie", "delta"}, gameNames(FilterGames(t.Games, PlayersBetween(1, 7))))
+	t.Assert().Equal([]string{"delta"}, gameNames(FilterGames(t.Games, GameVersion("V 001.003r"))))
+	t.Assert().Equal([]string{"Alpha", "bravo", "Charlie", "delta"}, gameNames(FilterGames(t.Games, GameName("es3a"))))
+	t.Assert().Equal([]string{"Alpha", "bravo", "delta"}, gameNames(FilterGames(t.Games, PingBelow(100*time.Millisecond))))
+	t.Assert().Equal([]string{"Charlie"}, gameNames(FilterGames(t.Games, NameContains("ARL"))))
+	t.Assert().Equal([]string{"bravo", "delta"}, gameNames(FilterGames(t.Games, NameMatches(regexp.MustCompile("^[a-z]")))))
+	t.Assert().Equal([]string{"Alpha", "Charlie"}, gameNames(FilterGames(t.Games, Or(HasStatus(protocol.Protected), Not(NotEmpty())))))
+	t.Assert().Empty(FilterGames(t.Games, Or()))
+}
+
+func (t *FilterTestSuite) TestFilterGames_NullPadded() {
+	t.Games[0].GameName = []byte("es3\x00")
+
+	t.Assert().Equal([]string{"Alpha"}, gameNames(FilterGames(t.Games, GameName("es3"))))
+}
+
+func (t *FilterTestSuite) TestSortGames() {
+	SortGames(t.Games, ByName)
+	t.Assert().Equal([]string{"Alpha", "bravo", "Charlie", "delta"}, gameNames(t.Games))
+
+	SortGames(t.Games, Reverse(ByPlayers), ByPing)
+	t.Assert().Equal([]string{"bravo", "delta", "Charlie", "Alpha"}, gameNames(t.Games))
+
+	// ties keep their order
+	SortGames(t.Games, ByPing)
+	t.Assert().Equal([]string{"bravo", "delta", "Alpha", "Charlie"}, gameNames(t.Games))
+}
+
+func TestFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(FilterTestSuite))
+}
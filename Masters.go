@@ -1,20 +1,20 @@
 package darkstar
 
 import (
+	"context"
+	"time"
+
 	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
-	"github.com/StarsiegePlayers/darkstar-query-go/v2/server"
 )
 
-func (q *Query) Masters() (output []*query.MasterQuery, games map[string]*server.Server, errorArray []error) {
-	availableMasters := len(q.Addresses)
-	await := make(chan *ServerResult)
-
-	for _, address := range q.Addresses {
-		go q.performMasterQuery(address, await)
-	}
+func (q *Query) Masters() (output []*query.MasterQuery, games map[string]*ListedServer, errorArray []error) {
+	return q.MastersContext(context.Background())
+}
 
-	for i := 0; i < availableMasters; i++ {
-		result := <-await
+// MastersContext is Masters, cancelling every outstanding query once ctx is done.
+// cancelled queries are reported in errorArray wrapping ctx.Err()
+func (q *Query) MastersContext(ctx context.Context) (output []*query.MasterQuery, games map[string]*ListedServer, errorArray []error) {
+	summary := q.StreamMasters(ctx, func(result *ServerResult) {
 		if result.Master.Ping > 0 {
 			output = append(output, result.Master)
 		}
@@ -22,36 +22,63 @@ func (q *Query) Masters() (output []*query.MasterQuery, games map[string]*server
 		if result.Error != nil {
 			errorArray = append(errorArray, result.Error)
 		}
-	}
+	})
 
-	close(await)
+	return output, summary.Games, errorArray
+}
 
-	games = q.dedupeMasterQuery(output)
+// MastersByAddress is MastersContext, returning the outcome of every master whether it answered or not
+func (q *Query) MastersByAddress(ctx context.Context) (output map[string]*ServerResult) {
+	output = make(map[string]*ServerResult)
+
+	q.StreamMasters(ctx, func(result *ServerResult) {
+		output[result.Address] = result
+	})
 
 	return
 }
 
-func (q *Query) dedupeMasterQuery(servers []*query.MasterQuery) (output map[string]*server.Server) {
-	output = make(map[string]*server.Server)
+// StreamMasters is MastersContext, calling callback with each result as soon as it completes.
+// callback is called from the calling goroutine, one result at a time.
+// the deduplicated game servers from every master that answered are in the returned Summary
+func (q *Query) StreamMasters(ctx context.Context, callback func(result *ServerResult)) (summary *Summary) {
+	addresses := make([]string, len(q.Addresses))
+	copy(addresses, q.Addresses)
+
+	summary = newSummary(len(addresses))
+	masters := make([]*query.MasterQuery, 0)
 
-	for _, svr := range servers {
-		for k, v := range svr.Servers {
-			if _, ok := output[k]; ok {
-				continue
-			}
+	await := q.fanOut(ctx, addresses, func(address string) *ServerResult {
+		return q.performMasterQuery(ctx, address)
+	})
 
-			output[k] = v
+	for i := 0; i < summary.Total; i++ {
+		result := <-await
+		summary.add(result)
+
+		if result.Master.Ping > 0 {
+			masters = append(masters, result.Master)
 		}
+
+		callback(result)
 	}
 
-	return output
+	close(await)
+
+	summary.Games = q.dedupeMasterQuery(masters)
+	summary.finish()
+
+	return
 }
 
-func (q *Query) performMasterQuery(address string, ret chan *ServerResult) {
-	r := new(ServerResult)
+func (q *Query) performMasterQuery(ctx context.Context, address string) *ServerResult {
+	r := &ServerResult{
+		Address: address,
+		Started: time.Now(),
+	}
 
 	r.Master = query.NewMasterQueryWithOptions(address, q.Options)
-	r.Error = r.Master.Query()
+	r.finish(r.Master.QueryContext(ctx), r.Master.Attempts())
 
-	ret <- r
+	return r
 }
@@ -0,0 +1,166 @@
+package darkstar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/mastertest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/server"
+	"github.com/stretchr/testify/suite"
+)
+
+type MastersTestSuite struct {
+	suite.Suite
+	Masters []*mastertest.Server
+	Query   *Query
+}
+
+func (t *MastersTestSuite) SetupTest() {
+	t.Masters = make([]*mastertest.Server, 0)
+	t.Query = NewQuery(200*time.Millisecond, false)
+}
+
+func (t *MastersTestSuite) TearDownTest() {
+	for _, v := range t.Masters {
+		v.Close()
+	}
+}
+
+// newMaster starts a master listing servers 96.126.117.[first, last]
+func (t *MastersTestSuite) newMaster(name string, first int, last int) *mastertest.Server {
+	master := protocol.NewMaster()
+	master.CommonName = name
+
+	for i := first; i <= last; i++ {
+		address := fmt.Sprintf("96.126.117.%d:29001", i)
+		master.Servers[address], _ = server.NewServerFromString(address)
+	}
+
+	s := mastertest.NewServer(master)
+
+	t.Masters = append(t.Masters, s)
+	t.Query.Addresses = append(t.Query.Addresses, s.Address)
+
+	return s
+}
+
+func (t *MastersTestSuite) TestMasters_Dedupe() {
+	t.newMaster("alpha", 1, 100)
+	t.newMaster("bravo", 51, 200)
+
+	masters, games, errs := t.Query.Masters()
+
+	t.Assert().Empty(errs)
+	t.Assert().Len(masters, 2)
+	t.Assert().Len(games, 200)
+	t.Assert().Contains(games, "96.126.117.1:29001")
+	t.Assert().Contains(games, "96.126.117.200:29001")
+
+	t.Assert().Len(games["96.126.117.1:29001"].Masters, 1)
+	t.Assert().Len(games["96.126.117.75:29001"].Masters, 2)
+	t.Assert().Equal("alpha", games["96.126.117.75:29001"].Masters[t.Masters[0].Address].CommonName)
+	t.Assert().Equal("bravo", games["96.126.117.75:29001"].Masters[t.Masters[1].Address].CommonName)
+}
+
+func (t *MastersTestSuite) TestMasters_Fragments() {
+	t.newMaster("alpha", 1, 200).SetFaults(mastertest.Faults{DropFragments: []byte{2}, Reverse: true})
+
+	masters, games, errs := t.Query.Masters()
+
+	t.Assert().Empty(errs)
+	t.Assert().Len(masters, 1)
+	t.Assert().Len(games, 200)
+}
+
+func (t *MastersTestSuite) TestStreamMasters() {
+	t.newMaster("alpha", 1, 100).SetFaults(mastertest.Faults{Delay: 50 * time.Millisecond})
+	t.newMaster("bravo", 51, 200)
+
+	order := make([]string, 0)
+
+	summary := t.Query.StreamMasters(context.Background(), func(result *ServerResult) {
+		t.Assert().Nil(result.Error)
+		order = append(order, result.Master.CommonName)
+	})
+
+	t.Assert().Equal([]string{"bravo", "alpha"}, order)
+	t.Assert().Equal(2, summary.Total)
+	t.Assert().Equal(2, summary.Succeeded)
+	t.Assert().Equal(0, summary.Failed)
+	t.Assert().Len(summary.Games, 200)
+}
+
+func (t *MastersTestSuite) TestMastersByAddress() {
+	alpha := t.newMaster("alpha", 1, 200)
+	alpha.SetFaults(mastertest.Faults{DropFragments: []byte{2}})
+	bravo := t.newMaster("bravo", 1, 10)
+	bravo.SetFaults(mastertest.Faults{Drop: 1})
+
+	t.Query.FragmentRetries = -1
+
+	results := t.Query.MastersByAddress(context.Background())
+	t.Require().Len(results, 2)
+
+	// partial lists are still reported with what did arrive
+	t.Assert().Equal(StatusPartial, results[alpha.Address].Status)
+	t.Assert().Equal("alpha", results[alpha.Address].Master.CommonName)
+	t.Assert().NotEmpty(results[alpha.Address].Master.Servers)
+
+	t.Assert().Equal(StatusTimeout, results[bravo.Address].Status)
+	t.Assert().Equal(1, results[bravo.Address].Attempts)
+}
+
+func (t *MastersTestSuite) TestMasters_Limited() {
+	t.Query.MaxInFlight = 1
+	t.Query.PacketsPerSecond = 50
+
+	t.newMaster("alpha", 1, 100)
+	t.newMaster("bravo", 51, 200)
+	t.newMaster("charlie", 150, 250)
+
+	masters, games, errs := t.Query.Masters()
+
+	t.Assert().Empty(errs)
+	t.Assert().Len(masters, 3)
+	t.Assert().Len(games, 250)
+}
+
+func (t *MastersTestSuite) TestMasters_Unreachable() {
+	t.newMaster("alpha", 1, 10)
+	t.newMaster("bravo", 11, 20).SetFaults(mastertest.Faults{Drop: 1})
+
+	masters, games, errs := t.Query.Masters()
+
+	t.Assert().Len(errs, 1)
+	t.Assert().Len(masters, 1)
+	t.Assert().Equal("alpha", masters[0].CommonName)
+	t.Assert().Len(games, 10)
+}
+
+func (t *MastersTestSuite) TestMastersContext_Deadline() {
+	t.Query.Timeout = 5 * time.Second
+
+	t.newMaster("alpha", 1, 10)
+	t.newMaster("bravo", 11, 20).SetFaults(mastertest.Faults{Delay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	masters, games, errs := t.Query.MastersContext(ctx)
+
+	t.Assert().Less(int64(time.Since(start)), int64(500*time.Millisecond))
+	t.Assert().Len(masters, 1)
+	t.Assert().Len(games, 10)
+	t.Require().Len(errs, 1)
+	t.Assert().True(errors.Is(errs[0], context.DeadlineExceeded))
+}
+
+func TestMastersTestSuite(t *testing.T) {
+	suite.Run(t, new(MastersTestSuite))
+}
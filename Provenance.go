@@ -0,0 +1,84 @@
+package darkstar
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/server"
+)
+
+// ListedServer is a game server from the deduplicated master lists along with every master advertising it
+type ListedServer struct {
+	*server.Server // the entry from the first master to answer
+
+	Masters map[string]*Listing // by master address
+}
+
+// Listing is a single master's entry for a game server
+type Listing struct {
+	Master     string // address of the master
+	CommonName string
+	MasterID   uint16
+	MOTD       string
+	LastSeen   time.Time
+}
+
+func newListing(master *query.MasterQuery, entry *server.Server) *Listing {
+	return &Listing{
+		Master:     master.Address,
+		CommonName: master.CommonName,
+		MasterID:   master.MasterID,
+		MOTD:       master.MOTD,
+		LastSeen:   entry.LastSeen,
+	}
+}
+
+// MasterAddresses returns the address of every master listing the server, sorted
+func (s *ListedServer) MasterAddresses() (output []string) {
+	output = make([]string, 0, len(s.Masters))
+
+	for k := range s.Masters {
+		output = append(output, k)
+	}
+
+	sort.Strings(output)
+
+	return
+}
+
+// MarshalJSON is needed as the embedded server.Server would otherwise marshal in place of the whole struct
+func (s *ListedServer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Address  string
+		LastSeen time.Time
+		Masters  map[string]*Listing
+	}{
+		Address:  s.Address.String(),
+		LastSeen: s.LastSeen,
+		Masters:  s.Masters,
+	})
+}
+
+func (q *Query) dedupeMasterQuery(servers []*query.MasterQuery) (output map[string]*ListedServer) {
+	output = make(map[string]*ListedServer)
+
+	for _, svr := range servers {
+		for k, v := range svr.Servers {
+			listed, ok := output[k]
+			if !ok {
+				listed = &ListedServer{
+					Server:  v,
+					Masters: make(map[string]*Listing),
+				}
+
+				output[k] = listed
+			}
+
+			listed.Masters[svr.Address] = newListing(svr, v)
+		}
+	}
+
+	return output
+}
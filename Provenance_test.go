@@ -0,0 +1,77 @@
+package darkstar
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/server"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProvenanceTestSuite struct {
+	suite.Suite
+	Masters []*query.MasterQuery
+}
+
+// addMaster appends a master listing games to masters, every master shares the MOTD "Welcome"
+func addMaster(masters *[]*query.MasterQuery, address string, name string, id uint16, games ...string) *query.MasterQuery {
+	m := query.NewMasterQuery(address)
+	m.CommonName = name
+	m.MOTD = "Welcome"
+	m.MasterID = id
+	m.Servers = server.NewServersMapFromList(games)
+
+	*masters = append(*masters, m)
+
+	return m
+}
+
+func (t *ProvenanceTestSuite) SetupTest() {
+	t.Masters = make([]*query.MasterQuery, 0)
+
+	addMaster(&t.Masters, "master2.starsiegeplayers.com:29000", "Bravo", 2, "96.126.117.1:29001", "96.126.117.2:29001")
+	addMaster(&t.Masters, "master1.starsiegeplayers.com:29000", "Alpha", 1, "96.126.117.2:29001", "96.126.117.3:29001")
+}
+
+func (t *ProvenanceTestSuite) TestDedupeMasterQuery() {
+	stale := time.Now().Add(-time.Hour)
+	t.Masters[1].Servers["96.126.117.2:29001"].LastSeen = stale
+
+	games := new(Query).dedupeMasterQuery(t.Masters)
+	t.Require().Len(games, 3)
+
+	t.Assert().Equal([]string{"master2.starsiegeplayers.com:29000"}, games["96.126.117.1:29001"].MasterAddresses())
+	t.Assert().Equal([]string{"master1.starsiegeplayers.com:29000"}, games["96.126.117.3:29001"].MasterAddresses())
+
+	shared := games["96.126.117.2:29001"]
+	t.Assert().Equal([]string{"master1.starsiegeplayers.com:29000", "master2.starsiegeplayers.com:29000"}, shared.MasterAddresses())
+
+	// the first master's entry is kept as the server, every master keeps its own LastSeen
+	t.Assert().Same(t.Masters[0].Servers["96.126.117.2:29001"], shared.Server)
+
+	listing := shared.Masters["master1.starsiegeplayers.com:29000"]
+	t.Assert().Equal("Alpha", listing.CommonName)
+	t.Assert().Equal(uint16(1), listing.MasterID)
+	t.Assert().Equal(stale, listing.LastSeen)
+	t.Assert().NotEqual(stale, shared.Masters["master2.starsiegeplayers.com:29000"].LastSeen)
+}
+
+func (t *ProvenanceTestSuite) TestListedServer_MarshalJSON() {
+	games := new(Query).dedupeMasterQuery(t.Masters)
+
+	data, err := json.Marshal(games["96.126.117.1:29001"])
+	t.Require().Nil(err)
+
+	output := make(map[string]interface{})
+	t.Require().Nil(json.Unmarshal(data, &output))
+
+	t.Assert().Equal("96.126.117.1:29001", output["Address"])
+	t.Assert().Contains(output, "LastSeen")
+	t.Assert().Contains(output["Masters"], "master2.starsiegeplayers.com:29000")
+}
+
+func TestProvenanceTestSuite(t *testing.T) {
+	suite.Run(t, new(ProvenanceTestSuite))
+}
@@ -1,6 +1,7 @@
 package darkstar
 
 import (
+	"net"
 	"time"
 
 	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
@@ -10,6 +11,11 @@ import (
 type Query struct {
 	*protocol.Options
 	Addresses []string
+
+	// Conn, when set, is used by Servers to query every address through the one socket
+	// instead of dialing each server separately. the caller owns Conn and must Close it.
+	// an unconnected socket isn't told when a port is closed, so those servers time out instead of being refused
+	Conn net.PacketConn
 }
 
 func NewQuery(timeout time.Duration, debug bool) *Query {
@@ -31,7 +37,54 @@ type Results struct {
 }
 
 type ServerResult struct {
-	Master *query.MasterQuery
-	Game   *query.PingInfoQuery
-	Error  error
+	Address string
+	Status  Status
+
+	Master   *query.MasterQuery
+	Game     *query.PingInfoQuery
+	GameInfo *query.GameInfoQuery
+	Error    error
+
+	Attempts int           // times the query was sent, see protocol.Options.Attempts
+	Started  time.Time     // when the query was started
+	Elapsed  time.Duration // time taken by every attempt, the round trip of the last is in Ping
+}
+
+// finish records the outcome of a query started at r.Started
+func (r *ServerResult) finish(err error, attempts int) {
+	r.Error = err
+	r.Status = statusOf(err)
+	r.Attempts = attempts
+	r.Elapsed = time.Since(r.Started)
+}
+
+// Summary is returned once every address of a streamed query has completed
+type Summary struct {
+	Total     int // addresses queried
+	Succeeded int
+	Failed    int
+	Elapsed   time.Duration
+
+	Games map[string]*ListedServer // deduplicated game servers, StreamMasters only
+
+	start time.Time
+}
+
+func newSummary(total int) *Summary {
+	return &Summary{
+		Total: total,
+		start: time.Now(),
+	}
+}
+
+func (s *Summary) add(result *ServerResult) {
+	if result.Error != nil {
+		s.Failed++
+	} else {
+		s.Succeeded++
+	}
+}
+
+func (s *Summary) finish() {
+	s.Elapsed = time.Since(s.start)
 }
@@ -1,40 +1,89 @@
 package darkstar
 
 import (
+	"context"
+	"time"
+
 	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
 )
 
 func (q *Query) Servers() (output []*query.PingInfoQuery, errors []error) {
-	availableServers := len(q.Addresses)
-	await := make(chan *ServerResult)
-
-	for _, game := range q.Addresses {
-		go q.performServerQuery(game, await)
-	}
+	return q.ServersContext(context.Background())
+}
 
-	for i := 0; i < availableServers; i++ {
-		result := <-await
+// ServersContext is Servers, cancelling every outstanding query once ctx is done.
+// cancelled queries are reported in errors wrapping ctx.Err()
+func (q *Query) ServersContext(ctx context.Context) (output []*query.PingInfoQuery, errors []error) {
+	q.StreamServers(ctx, func(result *ServerResult) {
 		if result.Error != nil {
 			errors = append(errors, result.Error)
 		} else {
 			output = append(output, result.Game)
 		}
+	})
+
+	return output, errors
+}
+
+// ServersByAddress is ServersContext, returning the outcome of every address whether it answered or not
+func (q *Query) ServersByAddress(ctx context.Context) (output map[string]*ServerResult) {
+	output = make(map[string]*ServerResult)
+
+	q.StreamServers(ctx, func(result *ServerResult) {
+		output[result.Address] = result
+	})
+
+	return
+}
+
+// StreamServers is ServersContext, calling callback with each result as soon as it completes.
+// callback is called from the calling goroutine, one result at a time
+func (q *Query) StreamServers(ctx context.Context, callback func(result *ServerResult)) (summary *Summary) {
+	addresses := make([]string, len(q.Addresses))
+	copy(addresses, q.Addresses)
+
+	summary = newSummary(len(addresses))
+
+	var mux *query.Mux
+	if q.Conn != nil {
+		mux = query.NewMux(q.Conn)
+		defer mux.Close()
+	}
+
+	await := q.fanOut(ctx, addresses, func(address string) *ServerResult {
+		return q.performServerQuery(ctx, mux, address)
+	})
+
+	for i := 0; i < summary.Total; i++ {
+		result := <-await
+		summary.add(result)
+
+		callback(result)
 	}
 
 	close(await)
 
-	return output, errors
+	summary.finish()
+
+	return
 }
 
-func (q *Query) performServerQuery(address string, ret chan *ServerResult) {
-	r := new(ServerResult)
+func (q *Query) performServerQuery(ctx context.Context, mux *query.Mux, address string) *ServerResult {
+	r := &ServerResult{
+		Address: address,
+		Started: time.Now(),
+	}
 
 	r.Game = query.NewPingInfoQueryWithOptions(address, q.Options)
 
-	err := r.Game.Query()
-	if err != nil {
-		r.Error = err
+	var err error
+	if mux != nil {
+		err = r.Game.QueryMux(ctx, mux)
+	} else {
+		err = r.Game.QueryContext(ctx)
 	}
 
-	ret <- r
+	r.finish(err, r.Game.Attempts())
+
+	return r
 }
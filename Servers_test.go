@@ -0,0 +1,270 @@
+package darkstar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServersTestSuite struct {
+	suite.Suite
+	Servers []*gametest.Server
+	Query   *Query
+}
+
+func (t *ServersTestSuite) SetupTest() {
+	t.Servers = make([]*gametest.Server, 0)
+	t.Query = NewQuery(200*time.Millisecond, false)
+}
+
+func (t *ServersTestSuite) TearDownTest() {
+	for _, v := range t.Servers {
+		v.Close()
+	}
+}
+
+func (t *ServersTestSuite) newServer(name string, faults gametest.Faults) *gametest.Server {
+	info := gametest.DefaultPingInfo()
+	info.Name = []byte(name)
+
+	s := gametest.NewServer(info)
+	s.SetFaults(faults)
+
+	t.Servers = append(t.Servers, s)
+	t.Query.Addresses = append(t.Query.Addresses, s.Address)
+
+	return s
+}
+
+func (t *ServersTestSuite) TestServers() {
+	t.newServer("alpha", gametest.Faults{})
+	t.newServer("bravo", gametest.Faults{Delay: 20 * time.Millisecond})
+	t.newServer("charlie", gametest.Faults{Duplicate: true})
+	t.newServer("dropped", gametest.Faults{Drop: 1})
+	t.newServer("malformed", gametest.Faults{Malformed: true})
+
+	games, errs := t.Query.Servers()
+
+	t.Assert().Equal([]string{"alpha", "bravo", "charlie"}, names(games))
+	t.Assert().Len(errs, 2)
+}
+
+func (t *ServersTestSuite) TestServers_Conn() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+
+	defer conn.Close()
+
+	t.Query.Conn = conn
+
+	t.TestServers()
+}
+
+func (t *ServersTestSuite) TestServers_Retry() {
+	t.Query.Attempts = 2
+	t.Query.AttemptTimeout = 100 * time.Millisecond
+
+	t.newServer("alpha", gametest.Faults{})
+	t.newServer("dropped", gametest.Faults{Drop: 1})
+	t.newServer("gone", gametest.Faults{Drop: 2})
+
+	games, errs := t.Query.Servers()
+
+	t.Assert().Equal([]string{"alpha", "dropped"}, names(games))
+	t.Assert().Len(errs, 1)
+
+	for _, v := range games {
+		if string(v.Name) == "dropped" {
+			t.Assert().Equal(2, v.Attempts())
+		}
+	}
+}
+
+func (t *ServersTestSuite) TestStreamServers() {
+	t.newServer("slow", gametest.Faults{Delay: 100 * time.Millisecond})
+	t.newServer("fast", gametest.Faults{})
+	t.newServer("dropped", gametest.Faults{Drop: 1})
+
+	start := time.Now()
+	order := make([]string, 0)
+
+	summary := t.Query.StreamServers(context.Background(), func(result *ServerResult) {
+		if result.Error != nil {
+			order = append(order, "error")
+			return
+		}
+
+		// fast results are delivered without waiting for the slower ones
+		if string(result.Game.Name) == "fast" {
+			t.Assert().Less(int64(time.Since(start)), int64(100*time.Millisecond))
+		}
+
+		order = append(order, string(result.Game.Name))
+	})
+
+	t.Assert().Equal([]string{"fast", "slow", "error"}, order)
+	t.Assert().Equal(3, summary.Total)
+	t.Assert().Equal(2, summary.Succeeded)
+	t.Assert().Equal(1, summary.Failed)
+	t.Assert().GreaterOrEqual(int64(summary.Elapsed), int64(t.Query.Timeout))
+	t.Assert().Nil(summary.Games)
+}
+
+func (t *ServersTestSuite) TestServersByAddress_Conn() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+
+	defer conn.Close()
+
+	t.Query.Conn = conn
+
+	// the shared socket isn't connected, so the closed port is never reported as refused
+	t.serversByAddress(StatusTimeout)
+}
+
+func (t *ServersTestSuite) TestServersByAddress() {
+	t.serversByAddress(StatusRefused)
+}
+
+// serversByAddress queries one of every kind of server, closedStatus is the one expected for a closed port
+func (t *ServersTestSuite) serversByAddress(closedStatus Status) {
+	alpha := t.newServer("alpha", gametest.Faults{})
+	dropped := t.newServer("dropped", gametest.Faults{Drop: 1})
+	malformed := t.newServer("malformed", gametest.Faults{Malformed: true})
+
+	// nothing listens on a closed port, so the query is refused
+	closed := t.newServer("closed", gametest.Faults{})
+	closed.Close()
+
+	t.Query.Addresses = append(t.Query.Addresses, "darkstar.invalid:29001")
+
+	results := t.Query.ServersByAddress(context.Background())
+	t.Require().Len(results, 5)
+
+	t.Assert().Equal(StatusOK, results[alpha.Address].Status)
+	t.Assert().Nil(results[alpha.Address].Error)
+	t.Assert().Equal("alpha", string(results[alpha.Address].Game.Name))
+	t.Assert().Equal(1, results[alpha.Address].Attempts)
+	t.Assert().GreaterOrEqual(int64(results[alpha.Address].Elapsed), int64(results[alpha.Address].Game.Ping))
+
+	t.Assert().Equal(StatusTimeout, results[dropped.Address].Status)
+	t.Assert().True(errors.Is(results[dropped.Address].Error, query.ErrorTimeout))
+	t.Assert().Equal(StatusMalformed, results[malformed.Address].Status)
+	t.Assert().Equal(closedStatus, results[closed.Address].Status)
+	t.Assert().Equal(StatusDNS, results["darkstar.invalid:29001"].Status)
+	t.Assert().Equal(0, results["darkstar.invalid:29001"].Attempts)
+
+	for k, v := range results {
+		t.Assert().Equal(k, v.Address)
+		t.Assert().False(v.Started.IsZero())
+	}
+}
+
+func (t *ServersTestSuite) TestServers_Empty() {
+	games, errs := t.Query.Servers()
+
+	t.Assert().Empty(games)
+	t.Assert().Empty(errs)
+}
+
+func (t *ServersTestSuite) TestServersContext_Cancel() {
+	t.Query.Timeout = 5 * time.Second
+
+	t.newServer("alpha", gametest.Faults{})
+	t.newServer("bravo", gametest.Faults{Delay: time.Second})
+	t.newServer("charlie", gametest.Faults{Drop: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+
+	games, errs := t.Query.ServersContext(ctx)
+
+	t.Assert().Less(int64(time.Since(start)), int64(500*time.Millisecond))
+	t.Assert().Equal([]string{"alpha"}, names(games))
+	t.Require().Len(errs, 2)
+
+	for _, v := range errs {
+		t.Assert().True(errors.Is(v, context.Canceled))
+	}
+}
+
+func (t *ServersTestSuite) TestServersContext_Done() {
+	t.newServer("alpha", gametest.Faults{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	games, errs := t.Query.ServersContext(ctx)
+
+	t.Assert().Empty(games)
+	t.Require().Len(errs, 1)
+	t.Assert().True(errors.Is(errs[0], context.Canceled))
+}
+
+func names(games []*query.PingInfoQuery) (output []string) {
+	output = make([]string, 0)
+
+	for _, v := range games {
+		output = append(output, string(v.Name))
+	}
+
+	sort.Strings(output)
+
+	return
+}
+
+func TestServersTestSuite(t *testing.T) {
+	suite.Run(t, new(ServersTestSuite))
+}
+
+func benchmarkServers(b *testing.B, shared bool) {
+	q := NewQuery(2*time.Second, false)
+
+	for i := 0; i < 50; i++ {
+		info := gametest.DefaultPingInfo()
+		info.Name = []byte(fmt.Sprintf("server %d", i))
+
+		s := gametest.NewServer(info)
+		defer s.Close()
+
+		q.Addresses = append(q.Addresses, s.Address)
+	}
+
+	if shared {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		defer conn.Close()
+
+		q.Conn = conn
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, errs := q.Servers()
+		if len(errs) > 0 {
+			b.Fatal(errs)
+		}
+	}
+}
+
+func BenchmarkServers_Dial(b *testing.B) {
+	benchmarkServers(b, false)
+}
+
+func BenchmarkServers_Conn(b *testing.B) {
+	benchmarkServers(b, true)
+}
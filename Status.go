@@ -0,0 +1,70 @@
+package darkstar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+)
+
+// Status is the outcome of querying a single address
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusTimeout
+	StatusDNS
+	StatusRefused
+	StatusMalformed
+	StatusPartial   // a master list with packets missing, what did arrive is in the result
+	StatusCancelled // the context was cancelled or its deadline passed
+	StatusError     // any other failure
+)
+
+var statusNames = map[Status]string{
+	StatusOK:        "ok",
+	StatusTimeout:   "timeout",
+	StatusDNS:       "dns",
+	StatusRefused:   "refused",
+	StatusMalformed: "malformed",
+	StatusPartial:   "partial",
+	StatusCancelled: "cancelled",
+	StatusError:     "error",
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// statusOf classifies the error returned by a query
+func statusOf(err error) Status {
+	var partial *query.PartialResultError
+
+	switch {
+	case err == nil:
+		return StatusOK
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return StatusCancelled
+	case errors.As(err, &partial):
+		return StatusPartial
+	case errors.Is(err, query.ErrorTimeout):
+		return StatusTimeout
+	case errors.Is(err, query.ErrorDNS):
+		return StatusDNS
+	case errors.Is(err, query.ErrorRefused):
+		return StatusRefused
+	case errors.Is(err, query.ErrorMalformedResponse):
+		return StatusMalformed
+	default:
+		return StatusError
+	}
+}
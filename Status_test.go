@@ -0,0 +1,38 @@
+package darkstar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatusTestSuite struct {
+	suite.Suite
+}
+
+func (t *StatusTestSuite) TestStatusOf() {
+	t.Assert().Equal(StatusOK, statusOf(nil))
+	t.Assert().Equal(StatusCancelled, statusOf(fmt.Errorf("pingInfo: [x]: %w", context.Canceled)))
+	t.Assert().Equal(StatusCancelled, statusOf(context.DeadlineExceeded))
+	t.Assert().Equal(StatusPartial, statusOf(fmt.Errorf("master: [x]: %w", &query.PartialResultError{Total: 2, Missing: []int{1}})))
+	t.Assert().Equal(StatusTimeout, statusOf(fmt.Errorf("pingInfo: [x]: %w", query.ErrorTimeout)))
+	t.Assert().Equal(StatusError, statusOf(errors.New("something else")))
+}
+
+func (t *StatusTestSuite) TestStatus_String() {
+	t.Assert().Equal("refused", StatusRefused.String())
+	t.Assert().Equal("Status(99)", Status(99).String())
+
+	data, err := json.Marshal(map[string]Status{"status": StatusMalformed})
+	t.Assert().Nil(err)
+	t.Assert().Equal(`{"status":"malformed"}`, string(data))
+}
+
+func TestStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusTestSuite))
+}
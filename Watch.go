@@ -0,0 +1,286 @@
+package darkstar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+)
+
+// EventType is the change to a game server reported by Watch
+type EventType int
+
+const (
+	EventOnline  EventType = iota // the server answered for the first time, or again after going offline
+	EventOffline                  // the server stopped answering, or is no longer listed by any master
+	EventPlayers                  // PlayerCount or MaxPlayers changed
+	EventName                     // Name changed
+	EventStatus                   // bits of GameStatus flipped, see Event.Flipped
+)
+
+var eventNames = map[EventType]string{
+	EventOnline:  "online",
+	EventOffline: "offline",
+	EventPlayers: "players",
+	EventName:    "name",
+	EventStatus:  "status",
+}
+
+func (e EventType) String() string {
+	if name, ok := eventNames[e]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("EventType(%d)", int(e))
+}
+
+func (e EventType) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// Event is a single change seen between two polls of a game server
+type Event struct {
+	Type    EventType
+	Address string
+	Time    time.Time
+
+	Game     *query.PingInfoQuery // the latest response, nil for EventOffline
+	Previous *query.PingInfoQuery // the response before it, nil for EventOnline
+	Changed  protocol.StatusByte  // the GameStatus bits that flipped, EventStatus only
+	Error    error                // why the server is offline, nil when it's no longer listed
+}
+
+// Flipped reports whether bit changed in this event
+func (e *Event) Flipped(bit protocol.StatusBit) bool {
+	return int(e.Changed)&int(bit) != 0
+}
+
+type WatchOptions struct {
+	Interval time.Duration // time between polls, defaults to the query Timeout
+	Misses   int           // consecutive polls a server can miss before it's offline, defaults to 1
+
+	// Masters, when set, are queried every Refresh and every server they list is watched
+	// along with Addresses. a master that doesn't answer a refresh keeps its previous list
+	Masters []string
+	Refresh time.Duration // time between master refreshes, defaults to every poll
+}
+
+// watched is the last known state of a game server
+type watched struct {
+	game   *query.PingInfoQuery // last response, nil until the server first answers
+	online bool
+	misses int
+}
+
+// Watch polls every address in Addresses, and every server listed by options.Masters, each
+// options.Interval until ctx is done, calling callback with every change it sees.
+// callback is called from the calling goroutine, one event at a time. Watch returns ctx.Err()
+func (q *Query) Watch(ctx context.Context, options WatchOptions, callback func(event *Event)) error {
+	if options.Interval <= 0 {
+		options.Interval = q.Timeout
+	}
+
+	if options.Misses <= 0 {
+		options.Misses = 1
+	}
+
+	state := make(map[string]*watched)
+	lists := make(map[string][]string)
+	listed := make([]string, 0)
+
+	var refreshed time.Time
+
+	ticker := time.NewTicker(options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if len(options.Masters) > 0 && time.Since(refreshed) >= options.Refresh {
+			refreshed = time.Now()
+
+			refreshLists(lists, q.withAddresses(options.Masters).MastersByAddress(ctx))
+			listed = mergeLists(lists)
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		results := q.withAddresses(watchAddresses(q.Addresses, listed)).ServersByAddress(ctx)
+
+		// a poll cut short by ctx says nothing about the servers it didn't hear from.
+		// the queries can time out on the ctx deadline before ctx.Err() reports it
+		if query.ContextError(ctx) != nil || cancelled(results) {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		for _, event := range diffWatched(state, results, options.Misses) {
+			callback(event)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// watchAddresses returns the static addresses and those listed by a master without duplicates
+func watchAddresses(static []string, listed []string) (output []string) {
+	seen := make(map[string]bool)
+	output = make([]string, 0, len(static)+len(listed))
+
+	for _, list := range [][]string{static, listed} {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				output = append(output, v)
+			}
+		}
+	}
+
+	return
+}
+
+// refreshLists replaces the list of every master that answered, a master that didn't keeps its previous list.
+// a partial list is added to the previous one, the servers it leaves out may just be in its missing packets
+func refreshLists(lists map[string][]string, results map[string]*ServerResult) {
+	for address, result := range results {
+		if result.Master == nil || result.Master.Ping <= 0 {
+			continue
+		}
+
+		list := make([]string, 0, len(result.Master.Servers))
+		for k := range result.Master.Servers {
+			list = append(list, k)
+		}
+
+		if result.Master.Partial {
+			list = append(list, lists[address]...)
+		}
+
+		lists[address] = list
+	}
+}
+
+// mergeLists returns every address listed by any master once, sorted
+func mergeLists(lists map[string][]string) (output []string) {
+	seen := make(map[string]bool)
+	output = make([]string, 0)
+
+	for _, list := range lists {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				output = append(output, v)
+			}
+		}
+	}
+
+	sort.Strings(output)
+
+	return
+}
+
+// cancelled reports whether any query of a poll was cut short by ctx
+func cancelled(results map[string]*ServerResult) bool {
+	for _, v := range results {
+		if v.Status == StatusCancelled {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffWatched updates state with the results of a poll, returning the events ordered by address.
+// servers in state that weren't polled are no longer watched
+func diffWatched(state map[string]*watched, results map[string]*ServerResult, misses int) (output []*Event) {
+	output = make([]*Event, 0)
+	now := time.Now()
+
+	addresses := make([]string, 0, len(results)+len(state))
+	for k := range results {
+		addresses = append(addresses, k)
+	}
+
+	for k := range state {
+		if _, ok := results[k]; !ok {
+			addresses = append(addresses, k)
+		}
+	}
+
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		w, ok := state[address]
+		if !ok {
+			w = new(watched)
+			state[address] = w
+		}
+
+		result, polled := results[address]
+		if !polled {
+			if w.online {
+				output = append(output, &Event{Type: EventOffline, Address: address, Time: now, Previous: w.game})
+			}
+
+			delete(state, address)
+
+			continue
+		}
+
+		if result.Error != nil {
+			w.misses++
+
+			if w.online && w.misses >= misses {
+				w.online = false
+				output = append(output, &Event{Type: EventOffline, Address: address, Time: now, Previous: w.game, Error: result.Error})
+			}
+
+			continue
+		}
+
+		previous := w.game
+		w.game = result.Game
+		w.misses = 0
+
+		if !w.online {
+			w.online = true
+			output = append(output, &Event{Type: EventOnline, Address: address, Time: now, Game: result.Game})
+
+			continue
+		}
+
+		output = append(output, changes(address, now, previous, result.Game)...)
+	}
+
+	return
+}
+
+// changes compares two responses from the same server
+func changes(address string, now time.Time, previous *query.PingInfoQuery, game *query.PingInfoQuery) (output []*Event) {
+	event := func(t EventType) *Event {
+		return &Event{Type: t, Address: address, Time: now, Game: game, Previous: previous}
+	}
+
+	if previous.PlayerCount != game.PlayerCount || previous.MaxPlayers != game.MaxPlayers {
+		output = append(output, event(EventPlayers))
+	}
+
+	if string(previous.Name) != string(game.Name) {
+		output = append(output, event(EventName))
+	}
+
+	if flipped := previous.GameStatus ^ game.GameStatus; flipped != 0 {
+		e := event(EventStatus)
+		e.Changed = flipped
+		output = append(output, e)
+	}
+
+	return
+}
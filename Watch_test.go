@@ -0,0 +1,258 @@
+package darkstar
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/mastertest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/server"
+	"github.com/stretchr/testify/suite"
+)
+
+type WatchTestSuite struct {
+	suite.Suite
+	Servers []*gametest.Server
+	Masters []*mastertest.Server
+	Query   *Query
+	Events  chan *Event
+
+	cancel context.CancelFunc
+	done   chan error
+}
+
+func (t *WatchTestSuite) SetupTest() {
+	t.Servers = make([]*gametest.Server, 0)
+	t.Masters = make([]*mastertest.Server, 0)
+	t.Query = NewQuery(100*time.Millisecond, false)
+	t.Events = make(chan *Event, 64)
+	t.cancel = nil
+}
+
+func (t *WatchTestSuite) TearDownTest() {
+	if t.cancel != nil {
+		t.cancel()
+		t.Assert().True(errors.Is(<-t.done, context.Canceled))
+	}
+
+	for _, v := range t.Servers {
+		v.Close()
+	}
+
+	for _, v := range t.Masters {
+		v.Close()
+	}
+}
+
+func (t *WatchTestSuite) newServer(name string, address string) *gametest.Server {
+	info := gametest.DefaultPingInfo()
+	info.Name = []byte(name)
+
+	s := gametest.NewUnstartedServer(info)
+	s.Address = address
+	s.Start()
+
+	t.Servers = append(t.Servers, s)
+
+	return s
+}
+
+func (t *WatchTestSuite) watch(options WatchOptions) {
+	var ctx context.Context
+
+	ctx, t.cancel = context.WithCancel(context.Background())
+	t.done = make(chan error, 1)
+
+	go func() {
+		t.done <- t.Query.Watch(ctx, options, func(event *Event) {
+			t.Events <- event
+		})
+	}()
+}
+
+// next waits for the next event, failing the test if none arrives
+func (t *WatchTestSuite) next() *Event {
+	select {
+	case event := <-t.Events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Require().FailNow("no event received")
+	}
+
+	return nil
+}
+
+func (t *WatchTestSuite) TestWatch() {
+	alpha := t.newServer("alpha", "")
+	bravo := t.newServer("bravo", "")
+	t.Query.Addresses = []string{alpha.Address, bravo.Address}
+
+	t.watch(WatchOptions{Interval: 20 * time.Millisecond})
+
+	online := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		event := t.next()
+		t.Assert().Equal(EventOnline, event.Type)
+		t.Assert().NotNil(event.Game)
+		t.Assert().Nil(event.Previous)
+		online[event.Address] = true
+	}
+
+	t.Assert().Equal(map[string]bool{alpha.Address: true, bravo.Address: true}, online)
+
+	info := gametest.DefaultPingInfo()
+	info.Name = []byte("alpha renamed")
+	info.PlayerCount = 3
+	info.GameStatus = protocol.StatusByte(protocol.Dedicated | protocol.Started | protocol.Protected)
+	alpha.SetPingInfo(info)
+
+	players := t.next()
+	t.Assert().Equal(EventPlayers, players.Type)
+	t.Assert().Equal(alpha.Address, players.Address)
+	t.Assert().Equal(byte(0), players.Previous.PlayerCount)
+	t.Assert().Equal(byte(3), players.Game.PlayerCount)
+
+	name := t.next()
+	t.Assert().Equal(EventName, name.Type)
+	t.Assert().Equal("alpha renamed", string(name.Game.Name))
+
+	status := t.next()
+	t.Assert().Equal(EventStatus, status.Type)
+	t.Assert().True(status.Flipped(protocol.Started))
+	t.Assert().True(status.Flipped(protocol.Protected))
+	t.Assert().False(status.Flipped(protocol.Dedicated))
+
+	bravo.SetFaults(gametest.Faults{Drop: 1000})
+
+	offline := t.next()
+	t.Assert().Equal(EventOffline, offline.Type)
+	t.Assert().Equal(bravo.Address, offline.Address)
+	t.Assert().True(errors.Is(offline.Error, query.ErrorTimeout))
+
+	bravo.SetFaults(gametest.Faults{})
+
+	back := t.next()
+	t.Assert().Equal(EventOnline, back.Type)
+	t.Assert().Equal(bravo.Address, back.Address)
+}
+
+func (t *WatchTestSuite) TestWatch_Masters() {
+	conn, err := net.ListenPacket("udp", crawlAddress)
+	if err != nil {
+		t.T().Skipf("%s isn't available: %s", crawlAddress, err)
+	}
+
+	_ = conn.Close()
+
+	alpha := t.newServer("alpha", crawlAddress)
+	bravo := t.newServer("bravo", crawlAddress)
+
+	master := protocol.NewMaster()
+	master.Servers[alpha.Address], _ = server.NewServerFromString(alpha.Address)
+	master.Servers[bravo.Address], _ = server.NewServerFromString(bravo.Address)
+
+	m := mastertest.NewServer(master)
+	t.Masters = append(t.Masters, m)
+
+	t.watch(WatchOptions{Interval: 20 * time.Millisecond, Masters: []string{m.Address}})
+
+	t.Assert().Equal(EventOnline, t.next().Type)
+	t.Assert().Equal(EventOnline, t.next().Type)
+
+	// servers no longer listed are dropped without an error
+	unlisted := protocol.NewMaster()
+	unlisted.Servers[alpha.Address], _ = server.NewServerFromString(alpha.Address)
+	m.SetMaster(unlisted)
+
+	offline := t.next()
+	t.Assert().Equal(EventOffline, offline.Type)
+	t.Assert().Equal(bravo.Address, offline.Address)
+	t.Assert().Nil(offline.Error)
+	t.Assert().Nil(offline.Game)
+	t.Assert().NotNil(offline.Previous)
+
+	// an unreachable master keeps the last list it gave
+	m.SetFaults(mastertest.Faults{Drop: 1000})
+
+	select {
+	case event := <-t.Events:
+		t.Assert().Failf("unexpected event", "%s %s", event.Type, event.Address)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func (t *WatchTestSuite) TestWatch_Deadline() {
+	// every poll is still waiting on the reply when the deadline passes
+	slow := t.newServer("slow", "")
+	slow.SetFaults(gametest.Faults{Delay: 60 * time.Millisecond})
+	t.Query.Addresses = []string{slow.Address}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	err := t.Query.Watch(ctx, WatchOptions{Interval: 20 * time.Millisecond}, func(event *Event) {
+		t.Events <- event
+	})
+	t.Assert().True(errors.Is(err, context.DeadlineExceeded))
+
+	// the poll cut short by the deadline doesn't count as a miss
+	close(t.Events)
+
+	events := make([]EventType, 0)
+	for event := range t.Events {
+		events = append(events, event.Type)
+	}
+
+	t.Assert().Equal([]EventType{EventOnline}, events)
+}
+
+func (t *WatchTestSuite) TestRefreshLists() {
+	answered := query.NewMasterQuery("answered")
+	answered.Ping = time.Millisecond
+	answered.Servers = server.NewServersMapFromList([]string{"96.126.117.1:29001"})
+
+	partial := query.NewMasterQuery("partial")
+	partial.Ping = time.Millisecond
+	partial.Partial = true
+	partial.Servers = server.NewServersMapFromList([]string{"96.126.117.3:29001"})
+
+	lists := map[string][]string{
+		"answered": {"96.126.117.1:29001", "96.126.117.2:29001"},
+		"failed":   {"96.126.117.4:29001"},
+		"partial":  {"96.126.117.5:29001"},
+	}
+
+	refreshLists(lists, map[string]*ServerResult{
+		"answered": {Master: answered},
+		"failed":   {Master: query.NewMasterQuery("failed"), Error: query.ErrorTimeout},
+		"partial":  {Master: partial},
+	})
+
+	t.Assert().Equal([]string{"96.126.117.1:29001"}, lists["answered"])
+	t.Assert().Equal([]string{"96.126.117.4:29001"}, lists["failed"])
+	t.Assert().ElementsMatch([]string{"96.126.117.3:29001", "96.126.117.5:29001"}, lists["partial"])
+
+	t.Assert().Equal([]string{"96.126.117.1:29001", "96.126.117.3:29001", "96.126.117.4:29001", "96.126.117.5:29001"}, mergeLists(lists))
+}
+
+func (t *WatchTestSuite) TestDiffWatched_Misses() {
+	state := make(map[string]*watched)
+	ok := &ServerResult{Address: "a", Game: query.NewPingInfoQuery("a")}
+	failed := &ServerResult{Address: "a", Error: query.ErrorTimeout}
+
+	t.Assert().Len(diffWatched(state, map[string]*ServerResult{"a": ok}, 2), 1)
+	t.Assert().Empty(diffWatched(state, map[string]*ServerResult{"a": failed}, 2))
+
+	events := diffWatched(state, map[string]*ServerResult{"a": failed}, 2)
+	t.Require().Len(events, 1)
+	t.Assert().Equal(EventOffline, events[0].Type)
+}
+
+func TestWatchTestSuite(t *testing.T) {
+	suite.Run(t, new(WatchTestSuite))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -48,28 +49,15 @@ func performServerListUpdate() ServerListData {
 		"starsiege.from-tx.com:29000",
 	}
 
-	masterServerInfo, gameAddresses, errs := q.Masters()
-	if len(errs) >= 0 {
-		for _, v := range errs {
-			errors = append(errors, v.Error())
-		}
-	}
-
-	q = darkstar.NewQuery(timeout, debug)
-	q.Addresses = []string{}
-	for k := range gameAddresses {
-		q.Addresses = append(q.Addresses, k)
-	}
-
-	games, errs := q.Servers()
-	for _, err := range errs {
-		errors = append(errors, err.Error())
+	snapshot := q.Crawl(context.Background(), darkstar.CrawlOptions{})
+	for _, v := range snapshot.Errors {
+		errors = append(errors, v.Error())
 	}
 
 	return ServerListData{
-		RequestTime: time.Now(),
-		Masters:     masterServerInfo,
-		Games:       games,
+		RequestTime: snapshot.RequestTime,
+		Masters:     snapshot.Masters,
+		Games:       snapshot.Games,
 		Errors:      errors,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
@@ -138,28 +139,6 @@ func rehashConfig(v *viper.Viper) {
 	thisMaster.Options.MaxServerPacketSize = config.MaxPacketSize
 	thisMaster.Unlock()
 
-	config.localNetworks = generateLocalAddresses()
+	config.localNetworks = query.LocalNetworks()
 	config.Unlock()
 }
-
-func generateLocalAddresses() (output []*net.IPNet) {
-	output = make([]*net.IPNet, 0)
-
-	ifaces, err := net.Interfaces()
-	if err != nil {
-		return
-	}
-
-	for _, i := range ifaces {
-		addrs, err := i.Addrs()
-		if err != nil {
-			continue
-		}
-
-		for k := range addrs {
-			output = append(output, addrs[k].(*net.IPNet))
-		}
-	}
-
-	return
-}
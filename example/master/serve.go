@@ -67,7 +67,15 @@ func serve(conn *net.PacketConn, addr net.Addr, buf []byte) {
 			return
 		}
 
-		registerHeartbeat(conn, addr, ipPort, p)
+		heartbeat, err := protocol.NewHeartbeatWithData(buf)
+		if err != nil {
+			LogServerAlert(ipPort, "Malformed heartbeat [%s]", err)
+			return
+		}
+
+		heartbeat.Address = ipPort
+
+		registerHeartbeat(conn, addr, ipPort, heartbeat)
 
 	// client is requesting a server list
 	case protocol.PingInfoQuery:
@@ -90,7 +98,7 @@ func serve(conn *net.PacketConn, addr net.Addr, buf []byte) {
 	}
 }
 
-func registerHeartbeat(conn *net.PacketConn, addr net.Addr, ipPort string, p *protocol.Packet) {
+func registerHeartbeat(conn *net.PacketConn, addr net.Addr, ipPort string, heartbeat *protocol.Heartbeat) {
 	thisMaster.Lock()
 	thisMaster.SolicitedServers[ipPort] = true
 	thisMaster.Unlock()
@@ -104,10 +112,10 @@ func registerHeartbeat(conn *net.PacketConn, addr net.Addr, ipPort string, p *pr
 		return
 	}
 
-	registerPingInfo(conn, addr, ipPort, p)
+	registerPingInfo(conn, addr, ipPort, heartbeat)
 }
 
-func registerPingInfo(conn *net.PacketConn, addr net.Addr, ipPort string, p *protocol.Packet) {
+func registerPingInfo(conn *net.PacketConn, addr net.Addr, ipPort string, heartbeat *protocol.Heartbeat) {
 	udpAddr := addr.(*net.UDPAddr)
 
 	thisMaster.Lock()
@@ -122,7 +130,7 @@ func registerPingInfo(conn *net.PacketConn, addr net.Addr, ipPort string, p *pro
 		}
 
 		// log and add new
-		LogServer(ipPort, "Heartbeat - New Server")
+		LogServer(ipPort, "Heartbeat - New Server [key: %d, id: %d]", heartbeat.Key, heartbeat.ID)
 
 		thisMaster.Service.Servers[ipPort] = &server.Server{
 			Address:    addr,
@@ -143,7 +151,7 @@ func registerPingInfo(conn *net.PacketConn, addr net.Addr, ipPort string, p *pro
 
 func sendList(conn *net.PacketConn, addr net.Addr, ipPort string, p *protocol.Packet) {
 	packets := thisMaster.Service.GeneratePackets(thisMaster.Options, p.Key, findLocalAddress(addr), addr)
-	for _, v := range packets {
+	for _, v := range protocol.RequestedPackets(packets, p.Number) {
 		_, err := (*conn).WriteTo(v, addr)
 		if err != nil {
 			LogServerAlert(ipPort, "error sending list packet [%s]", err)
@@ -156,7 +164,7 @@ func sendList(conn *net.PacketConn, addr net.Addr, ipPort string, p *protocol.Pa
 
 func sendBanned(conn *net.PacketConn, addr net.Addr, ipPort string, p *protocol.Packet) {
 	packets := thisMaster.BannedService.GeneratePackets(thisMaster.Options, p.Key, nil, addr)
-	for _, v := range packets {
+	for _, v := range protocol.RequestedPackets(packets, p.Number) {
 		_, err := (*conn).WriteTo(v, addr)
 		if err != nil {
 			LogServerAlert(ipPort, "error sending banned message packet [%s]", err)
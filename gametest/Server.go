@@ -0,0 +1,134 @@
+package gametest
+
+import (
+	"net"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/internal/udptest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// Faults are injected into every response the Server sends
+type Faults struct {
+	Delay     time.Duration // wait before answering
+	Drop      int           // ignore the next n queries
+	Duplicate bool          // send every response twice
+	Malformed bool          // truncate responses so they fail to decode
+}
+
+// Server is a fake darkstar game server listening on an ephemeral localhost port,
+// answering PingInfoQuery and GameInfoQuery packets for use in tests
+type Server struct {
+	*udptest.Server
+
+	PingInfo *protocol.PingInfo
+	GameInfo *protocol.GameInfo // nil ignores GameInfoQuery packets
+
+	faults Faults
+}
+
+// DefaultPingInfo returns the PingInfo a Server answers with when none is given
+func DefaultPingInfo() *protocol.PingInfo {
+	return &protocol.PingInfo{
+		GameMode:    0xfd,
+		GameName:    []byte("es3a"),
+		GameVersion: []byte("V 001.004r"),
+		GameStatus:  protocol.StatusByte(protocol.Dedicated),
+		PlayerCount: 0,
+		MaxPlayers:  16,
+		Name:        []byte("gametest server"),
+	}
+}
+
+// NewServer starts and returns a new Server, the caller should Close it when finished
+func NewServer(info *protocol.PingInfo) *Server {
+	s := NewUnstartedServer(info)
+	s.Start()
+
+	return s
+}
+
+// NewUnstartedServer returns a Server that doesn't listen until Start is called
+func NewUnstartedServer(info *protocol.PingInfo) *Server {
+	if info == nil {
+		info = DefaultPingInfo()
+	}
+
+	s := &Server{
+		PingInfo: info,
+	}
+
+	s.Server = udptest.NewServer("gametest", s.handle)
+
+	return s
+}
+
+func (s *Server) SetFaults(faults Faults) {
+	s.Lock()
+	s.faults = faults
+	s.Unlock()
+}
+
+func (s *Server) SetPingInfo(info *protocol.PingInfo) {
+	s.Lock()
+	s.PingInfo = info
+	s.Unlock()
+}
+
+func (s *Server) SetGameInfo(info *protocol.GameInfo) {
+	s.Lock()
+	s.GameInfo = info
+	s.Unlock()
+}
+
+func (s *Server) handle(request *protocol.Packet, _ net.Addr) *udptest.Response {
+	if s.faults.Drop > 0 {
+		s.faults.Drop--
+		return nil
+	}
+
+	response, err := s.response(request)
+	if err != nil || response == nil {
+		return nil
+	}
+
+	if s.faults.Malformed {
+		response = response[:protocol.HeaderSize+3]
+	}
+
+	return &udptest.Response{
+		Packets:   [][]byte{response},
+		Delay:     s.faults.Delay,
+		Duplicate: s.faults.Duplicate,
+	}
+}
+
+func (s *Server) response(request *protocol.Packet) ([]byte, error) {
+	switch request.Type {
+	case protocol.PingInfoQuery:
+		if s.PingInfo == nil {
+			return nil, nil
+		}
+
+		response := *s.PingInfo
+		response.Packet = protocol.NewPacket()
+		response.Key = request.Key
+		response.Number = protocol.RequestAllPackets
+
+		return response.MarshalBinary()
+
+	case protocol.GameInfoQuery:
+		if s.GameInfo == nil {
+			return nil, nil
+		}
+
+		response := *s.GameInfo
+		response.Packet = protocol.NewPacket()
+		response.Key = request.Key
+		response.Number = protocol.RequestAllPackets
+
+		return response.MarshalBinary()
+	}
+
+	return nil, nil
+}
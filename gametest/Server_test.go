@@ -0,0 +1,119 @@
+package gametest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	Server  *Server
+	Options *protocol.Options
+}
+
+func (t *ServerTestSuite) SetupTest() {
+	t.Server = NewServer(nil)
+	t.Options = &protocol.Options{
+		Timeout: 200 * time.Millisecond,
+	}
+}
+
+func (t *ServerTestSuite) TearDownTest() {
+	t.Server.Close()
+}
+
+func (t *ServerTestSuite) TestServer_PingInfo() {
+	q := query.NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+	err := q.Query()
+	t.Require().Nil(err)
+
+	t.Assert().Equal(DefaultPingInfo().Name, q.Name)
+	t.Assert().Equal(DefaultPingInfo().MaxPlayers, q.MaxPlayers)
+	t.Assert().Len(t.Server.Requests(), 1)
+	t.Assert().Equal(protocol.PingInfoQuery, t.Server.Requests()[0].Type)
+}
+
+func (t *ServerTestSuite) TestServer_GameInfo() {
+	q := query.NewGameInfoQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().NotNil(q.Query())
+
+	info := protocol.NewGameInfo()
+	info.GameName = "es3a"
+	info.Name = "gametest server"
+	info.Payload = []byte{0x01, 0x02, 0x03}
+	t.Server.SetGameInfo(info)
+
+	q = query.NewGameInfoQueryWithOptions(t.Server.Address, t.Options)
+	err := q.Query()
+	t.Require().Nil(err)
+
+	t.Assert().Equal(info.Name, q.Name)
+	t.Assert().Equal(info.Payload, q.Payload)
+}
+
+func (t *ServerTestSuite) TestServer_Drop() {
+	t.Server.SetFaults(Faults{Drop: 1})
+
+	q := query.NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Contains(q.Query().Error(), "connection timed out")
+
+	q = query.NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Nil(q.Query())
+}
+
+func (t *ServerTestSuite) TestServer_Delay() {
+	t.Server.SetFaults(Faults{Delay: 50 * time.Millisecond})
+
+	q := query.NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Nil(q.Query())
+	t.Assert().GreaterOrEqual(int64(q.Ping), int64(50*time.Millisecond))
+
+	t.Server.SetFaults(Faults{Delay: 2 * t.Options.Timeout})
+
+	q = query.NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().NotNil(q.Query())
+}
+
+func (t *ServerTestSuite) TestServer_Malformed() {
+	t.Server.SetFaults(Faults{Malformed: true})
+
+	q := query.NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Contains(q.Query().Error(), "unspecified error parsing ping response")
+}
+
+func (t *ServerTestSuite) TestServer_Duplicate() {
+	t.Server.SetFaults(Faults{Duplicate: true})
+
+	conn, err := net.Dial("udp", t.Server.Address)
+	t.Require().Nil(err)
+
+	defer conn.Close()
+
+	packet := protocol.NewPacket()
+	packet.Type = protocol.PingInfoQuery
+	request, _ := packet.MarshalBinary()
+
+	_, err = conn.Write(request)
+	t.Require().Nil(err)
+
+	_ = conn.SetDeadline(time.Now().Add(t.Options.Timeout))
+
+	buf := make([]byte, protocol.MaxPacketSize)
+	for i := 0; i < 2; i++ {
+		n, err := conn.Read(buf)
+		t.Require().Nil(err)
+
+		response, err := protocol.NewPacketWithData(buf[:n])
+		t.Require().Nil(err)
+		t.Assert().Equal(protocol.PingInfoResponse, response.Type)
+	}
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
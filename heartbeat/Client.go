@@ -0,0 +1,323 @@
+package heartbeat
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+const DefaultInterval = 2 * time.Minute
+
+// a failing socket is retried after minReadBackoff, doubling up to maxReadBackoff while it keeps failing
+const (
+	minReadBackoff = 10 * time.Millisecond
+	maxReadBackoff = time.Second
+)
+
+var ErrorAlreadyRunning = errors.New("heartbeat client already running")
+
+// Status is the registration state of the client with a single master
+type Status struct {
+	Address      string
+	LastSent     time.Time // last heartbeat sent to the master
+	LastVerified time.Time // last PingInfoQuery answered from the master's address
+	Registered   bool      // verified within the last two heartbeat intervals
+	Error        error     // last resolve or send error, cleared on success
+}
+
+// Client registers a game server with a list of masters.
+// heartbeats are sent from the same local udp port that answers the masters' PingInfoQuery verification,
+// the same way a real game server does it
+type Client struct {
+	sync.Mutex
+
+	LocalAddress string
+	Masters      []string
+	Interval     time.Duration
+
+	pingInfo *protocol.PingInfo
+	gameInfo *protocol.GameInfo
+
+	conn     net.PacketConn
+	masters  []string
+	resolved map[string]net.IP
+	status   map[string]*Status
+	txID     uint16
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func NewClient(localAddress string, info *protocol.PingInfo, masters ...string) *Client {
+	return &Client{
+		LocalAddress: localAddress,
+		Masters:      masters,
+		Interval:     DefaultInterval,
+		pingInfo:     info,
+		txID:         protocol.RandomKey(),
+		resolved:     make(map[string]net.IP),
+		status:       make(map[string]*Status),
+	}
+}
+
+// SetPingInfo replaces the PingInfoResponse sent to anyone querying the server
+func (c *Client) SetPingInfo(info *protocol.PingInfo) {
+	c.Lock()
+	c.pingInfo = info
+	c.Unlock()
+}
+
+// SetGameInfo enables answering GameInfoQuery packets, nil disables it.
+// info.Payload is sent as given after the header, nothing encodes it yet
+func (c *Client) SetGameInfo(info *protocol.GameInfo) {
+	c.Lock()
+	c.gameInfo = info
+	c.Unlock()
+}
+
+// Addr returns the bound local address, or nil if the client isn't running
+func (c *Client) Addr() net.Addr {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.LocalAddr()
+}
+
+func (c *Client) Start() (err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.conn != nil {
+		return ErrorAlreadyRunning
+	}
+
+	c.conn, err = net.ListenPacket("udp", c.LocalAddress)
+	if err != nil {
+		c.conn = nil
+		return
+	}
+
+	// changes to Masters take effect on the next Start
+	c.masters = make([]string, len(c.Masters))
+	copy(c.masters, c.Masters)
+	c.resolved = make(map[string]net.IP)
+
+	for _, v := range c.masters {
+		if _, exists := c.status[v]; !exists {
+			c.status[v] = &Status{Address: v}
+		}
+	}
+
+	c.done = make(chan struct{})
+
+	c.wg.Add(2)
+
+	go c.listen(c.conn, c.done)
+	go c.send(c.conn, c.done)
+
+	return nil
+}
+
+func (c *Client) Stop() error {
+	c.Lock()
+
+	if c.conn == nil {
+		c.Unlock()
+		return nil
+	}
+
+	close(c.done)
+	err := c.conn.Close()
+	c.conn = nil
+	c.Unlock()
+
+	c.wg.Wait()
+
+	return err
+}
+
+// Status returns a snapshot of the registration state for every master
+func (c *Client) Status() map[string]Status {
+	c.Lock()
+	defer c.Unlock()
+
+	output := make(map[string]Status)
+
+	for k, v := range c.status {
+		s := *v
+		s.Registered = !s.LastVerified.IsZero() && time.Since(s.LastVerified) < 2*c.interval()
+		output[k] = s
+	}
+
+	return output
+}
+
+func (c *Client) interval() time.Duration {
+	if c.Interval <= 0 {
+		return DefaultInterval
+	}
+
+	return c.Interval
+}
+
+func (c *Client) send(conn net.PacketConn, done chan struct{}) {
+	defer c.wg.Done()
+
+	c.sendHeartbeats(conn)
+
+	c.Lock()
+	ticker := time.NewTicker(c.interval())
+	c.Unlock()
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			c.sendHeartbeats(conn)
+		}
+	}
+}
+
+func (c *Client) sendHeartbeats(conn net.PacketConn) {
+	c.Lock()
+	heartbeat := protocol.NewHeartbeat()
+	heartbeat.Key = c.txID
+	c.txID++
+	c.Unlock()
+
+	data, err := heartbeat.MarshalBinary()
+	if err != nil {
+		return
+	}
+
+	// masters are re-resolved every round in case their dns changes
+	for _, master := range c.masters {
+		address, err := net.ResolveUDPAddr("udp", master)
+		if err == nil {
+			_, err = conn.WriteTo(data, address)
+		}
+
+		c.Lock()
+		s := c.status[master]
+		s.Error = err
+
+		if err == nil {
+			s.LastSent = time.Now()
+			c.resolved[master] = address.IP
+		}
+		c.Unlock()
+	}
+}
+
+func (c *Client) listen(conn net.PacketConn, done chan struct{}) {
+	defer c.wg.Done()
+
+	buf := make([]byte, protocol.MaxPacketSize)
+	backoff := minReadBackoff
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			// some platforms surface icmp unreachable from a down master as a read error,
+			// anything that keeps failing is retried more slowly rather than spinning
+			select {
+			case <-done:
+				return
+			case <-time.After(backoff):
+			}
+
+			if backoff *= 2; backoff > maxReadBackoff {
+				backoff = maxReadBackoff
+			}
+
+			continue
+		}
+
+		backoff = minReadBackoff
+
+		request, err := protocol.NewPacketWithData(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		switch request.Type {
+		case protocol.PingInfoQuery:
+			c.answerPingInfo(conn, addr, request)
+			c.verified(addr)
+
+		case protocol.GameInfoQuery:
+			c.answerGameInfo(conn, addr, request)
+		}
+	}
+}
+
+func (c *Client) answerPingInfo(conn net.PacketConn, addr net.Addr, request *protocol.Packet) {
+	c.Lock()
+	if c.pingInfo == nil {
+		c.Unlock()
+		return
+	}
+
+	response := *c.pingInfo
+	c.Unlock()
+
+	response.Packet = protocol.NewPacket()
+	response.Key = request.Key
+	response.Number = protocol.RequestAllPackets
+
+	data, err := response.MarshalBinary()
+	if err != nil {
+		return
+	}
+
+	_, _ = conn.WriteTo(data, addr)
+}
+
+func (c *Client) answerGameInfo(conn net.PacketConn, addr net.Addr, request *protocol.Packet) {
+	c.Lock()
+	if c.gameInfo == nil {
+		c.Unlock()
+		return
+	}
+
+	response := *c.gameInfo
+	c.Unlock()
+
+	response.Packet = protocol.NewPacket()
+	response.Key = request.Key
+	response.Number = protocol.RequestAllPackets
+
+	data, err := response.MarshalBinary()
+	if err != nil {
+		return
+	}
+
+	_, _ = conn.WriteTo(data, addr)
+}
+
+// verified marks every master resolving to the querying ip as having verified us.
+// masters verify from a fresh socket, so only the ip is compared
+func (c *Client) verified(addr net.Addr) {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for master, ip := range c.resolved {
+		if ip.Equal(udpAddr.IP) {
+			c.status[master].LastVerified = time.Now()
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package heartbeat
+
+import (
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	Master     net.PacketConn
+	Heartbeats chan *protocol.Heartbeat
+	Client     *Client
+	PingInfo   *protocol.PingInfo
+}
+
+func (t *ClientTestSuite) SetupTest() {
+	var err error
+
+	t.Master, err = net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+
+	t.Heartbeats = make(chan *protocol.Heartbeat, 16)
+
+	t.PingInfo = &protocol.PingInfo{
+		GameName:    []byte("es3a"),
+		GameVersion: []byte("V 001.004r"),
+		GameStatus:  protocol.StatusByte(protocol.Dedicated),
+		PlayerCount: 3,
+		MaxPlayers:  16,
+		Name:        []byte("Heartbeat Test Server"),
+	}
+
+	t.Client = NewClient("127.0.0.1:0", t.PingInfo, t.Master.LocalAddr().String())
+	t.Client.Interval = 100 * time.Millisecond
+
+	go t.serve()
+}
+
+func (t *ClientTestSuite) TearDownTest() {
+	_ = t.Client.Stop()
+	_ = t.Master.Close()
+}
+
+// serve verifies every heartbeat the same way example/master does
+func (t *ClientTestSuite) serve() {
+	buf := make([]byte, protocol.MaxPacketSize)
+
+	for {
+		n, addr, err := t.Master.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		heartbeat, err := protocol.NewHeartbeatWithData(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		heartbeat.Address = addr.String()
+
+		q := query.NewPingInfoQuery(heartbeat.Address)
+		if q.Query() != nil {
+			continue
+		}
+
+		t.Heartbeats <- heartbeat
+	}
+}
+
+func (t *ClientTestSuite) TestClient_Registers() {
+	err := t.Client.Start()
+	t.Require().Nil(err)
+
+	heartbeat := <-t.Heartbeats
+	t.Assert().Equal(t.Client.Addr().String(), heartbeat.Address)
+
+	t.Assert().Eventually(func() bool {
+		return t.Client.Status()[t.Master.LocalAddr().String()].Registered
+	}, time.Second, 10*time.Millisecond)
+
+	status := t.Client.Status()[t.Master.LocalAddr().String()]
+	t.Assert().Nil(status.Error)
+	t.Assert().False(status.LastSent.IsZero())
+
+	// heartbeats keep coming on the interval with a new key each time
+	next := <-t.Heartbeats
+	t.Assert().NotEqual(heartbeat.Key, next.Key)
+}
+
+func (t *ClientTestSuite) TestClient_AnswersPingInfo() {
+	err := t.Client.Start()
+	t.Require().Nil(err)
+
+	q := query.NewPingInfoQuery(t.Client.Addr().String())
+	err = q.Query()
+	t.Require().Nil(err)
+
+	t.Assert().Equal(t.PingInfo.Name, q.Name)
+	t.Assert().Equal(t.PingInfo.PlayerCount, q.PlayerCount)
+	t.Assert().Equal(t.PingInfo.MaxPlayers, q.MaxPlayers)
+	t.Assert().Equal(t.PingInfo.GameStatus, q.GameStatus)
+}
+
+func (t *ClientTestSuite) TestClient_AnswersGameInfo() {
+	err := t.Client.Start()
+	t.Require().Nil(err)
+
+	options := &protocol.Options{Timeout: 100 * time.Millisecond}
+
+	q := query.NewGameInfoQueryWithOptions(t.Client.Addr().String(), options)
+	t.Assert().NotNil(q.Query())
+
+	info := protocol.NewGameInfo()
+	info.GameName = "es3a"
+	info.Name = "Heartbeat Test Server"
+	info.Payload = []byte{0x01, 0x02, 0x03}
+	t.Client.SetGameInfo(info)
+
+	q = query.NewGameInfoQueryWithOptions(t.Client.Addr().String(), options)
+	err = q.Query()
+	t.Require().Nil(err)
+
+	t.Assert().Equal(info.Name, q.Name)
+	t.Assert().Equal(info.Payload, q.Payload)
+}
+
+func (t *ClientTestSuite) TestClient_StartStop() {
+	err := t.Client.Start()
+	t.Require().Nil(err)
+
+	err = t.Client.Start()
+	t.Assert().ErrorIs(err, ErrorAlreadyRunning)
+
+	err = t.Client.Stop()
+	t.Assert().Nil(err)
+	t.Assert().Nil(t.Client.Addr())
+
+	err = t.Client.Stop()
+	t.Assert().Nil(err)
+
+	err = t.Client.Start()
+	t.Assert().Nil(err)
+}
+
+func (t *ClientTestSuite) TestClient_BadMaster() {
+	t.Client.Masters = append(t.Client.Masters, "127.0.0.1")
+
+	err := t.Client.Start()
+	t.Require().Nil(err)
+
+	<-t.Heartbeats
+
+	status := t.Client.Status()
+	t.Assert().Len(status, 2)
+	t.Assert().NotNil(status["127.0.0.1"].Error)
+	t.Assert().False(status["127.0.0.1"].Registered)
+	t.Assert().True(status["127.0.0.1"].LastSent.IsZero())
+}
+
+// failingConn fails every read, counting how often it was called
+type failingConn struct {
+	net.PacketConn
+	reads int32
+}
+
+func (c *failingConn) ReadFrom(_ []byte) (int, net.Addr, error) {
+	atomic.AddInt32(&c.reads, 1)
+
+	return 0, nil, syscall.ECONNREFUSED
+}
+
+func (t *ClientTestSuite) TestClient_ListenBackoff() {
+	conn := new(failingConn)
+	done := make(chan struct{})
+
+	t.Client.wg.Add(1)
+
+	go t.Client.listen(conn, done)
+
+	time.Sleep(200 * time.Millisecond)
+	close(done)
+	t.Client.wg.Wait()
+
+	// 10 + 20 + 40 + 80ms fit in 200ms, a spinning loop would read thousands of times
+	t.Assert().LessOrEqual(atomic.LoadInt32(&conn.reads), int32(6))
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
@@ -0,0 +1,139 @@
+package udptest
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// Response is sent back to the address a request came from
+type Response struct {
+	Packets   [][]byte
+	Delay     time.Duration // wait before answering
+	Duplicate bool          // send every packet twice
+}
+
+// Handler answers a request, nil sends nothing. it's called with the Server locked
+type Handler func(request *protocol.Packet, addr net.Addr) *Response
+
+// Server is the udp listener shared by gametest and mastertest, recording every well formed packet
+// it receives and answering it with Handler
+type Server struct {
+	sync.Mutex
+
+	Address string // ip:port of the listening server, set before Start to listen somewhere other than an ephemeral localhost port
+
+	name     string // prefixes panics, the package embedding the Server
+	handler  Handler
+	conn     net.PacketConn
+	requests []*protocol.Packet
+	wg       sync.WaitGroup
+}
+
+func NewServer(name string, handler Handler) *Server {
+	return &Server{
+		name:     name,
+		handler:  handler,
+		requests: make([]*protocol.Packet, 0),
+	}
+}
+
+func (s *Server) Start() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.conn != nil {
+		panic(s.name + ": Server already started")
+	}
+
+	address := s.Address
+	if address == "" {
+		address = "127.0.0.1:0"
+	}
+
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		panic(s.name + ": failed to listen on a port: " + err.Error())
+	}
+
+	s.conn = conn
+	s.Address = conn.LocalAddr().String()
+
+	s.wg.Add(1)
+
+	go s.serve(conn)
+}
+
+// Close stops the server and waits for any pending responses
+func (s *Server) Close() {
+	s.Lock()
+	conn := s.conn
+	s.Unlock()
+
+	if conn == nil {
+		return
+	}
+
+	_ = conn.Close()
+	s.wg.Wait()
+}
+
+// Requests returns every well formed packet the server has received so far
+func (s *Server) Requests() []*protocol.Packet {
+	s.Lock()
+	defer s.Unlock()
+
+	output := make([]*protocol.Packet, len(s.requests))
+	copy(output, s.requests)
+
+	return output
+}
+
+func (s *Server) serve(conn net.PacketConn) {
+	defer s.wg.Done()
+
+	buf := make([]byte, protocol.MaxPacketSize)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		request, err := protocol.NewPacketWithData(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		s.Lock()
+		s.requests = append(s.requests, request)
+		response := s.handler(request, addr)
+		s.Unlock()
+
+		if response == nil || len(response.Packets) == 0 {
+			continue
+		}
+
+		s.wg.Add(1)
+
+		go s.respond(conn, addr, response)
+	}
+}
+
+func (s *Server) respond(conn net.PacketConn, addr net.Addr, response *Response) {
+	defer s.wg.Done()
+
+	if response.Delay > 0 {
+		time.Sleep(response.Delay)
+	}
+
+	for _, v := range response.Packets {
+		_, _ = conn.WriteTo(v, addr)
+
+		if response.Duplicate {
+			_, _ = conn.WriteTo(v, addr)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package udptest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	Server *Server
+	Conn   net.PacketConn
+}
+
+func (t *ServerTestSuite) SetupTest() {
+	var err error
+
+	t.Server = NewServer("udptest", func(request *protocol.Packet, addr net.Addr) *Response {
+		if request.Type != protocol.PingInfoQuery {
+			return nil
+		}
+
+		data, _ := request.MarshalBinary()
+
+		return &Response{Packets: [][]byte{data}, Duplicate: request.Number == 2}
+	})
+	t.Server.Start()
+
+	t.Conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+}
+
+func (t *ServerTestSuite) TearDownTest() {
+	t.Server.Close()
+	_ = t.Conn.Close()
+}
+
+func (t *ServerTestSuite) send(packetType protocol.PacketType, number byte) {
+	p := protocol.NewPacket()
+	p.Type = packetType
+	p.Number = number
+
+	data, err := p.MarshalBinary()
+	t.Require().Nil(err)
+
+	address, err := net.ResolveUDPAddr("udp", t.Server.Address)
+	t.Require().Nil(err)
+
+	_, err = t.Conn.WriteTo(data, address)
+	t.Require().Nil(err)
+}
+
+// received counts the packets that arrive before the connection goes quiet
+func (t *ServerTestSuite) received() (count int) {
+	buf := make([]byte, protocol.MaxPacketSize)
+
+	for {
+		_ = t.Conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+
+		if _, _, err := t.Conn.ReadFrom(buf); err != nil {
+			return
+		}
+
+		count++
+	}
+}
+
+func (t *ServerTestSuite) TestServer() {
+	t.send(protocol.PingInfoQuery, 1)
+	t.Assert().Equal(1, t.received())
+
+	t.send(protocol.PingInfoQuery, 2)
+	t.Assert().Equal(2, t.received())
+
+	t.send(protocol.MasterServerHeartbeat, 1)
+	t.Assert().Equal(0, t.received())
+
+	requests := t.Server.Requests()
+	t.Require().Len(requests, 3)
+	t.Assert().Equal(protocol.MasterServerHeartbeat, requests[2].Type)
+}
+
+func (t *ServerTestSuite) TestServer_StartTwice() {
+	t.Assert().Panics(t.Server.Start)
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
@@ -0,0 +1,125 @@
+package mastertest
+
+import (
+	"net"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/internal/udptest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// DefaultMaxServerPacketSize matches the default MaxPacketSize of example/master
+const DefaultMaxServerPacketSize = 512
+
+// Faults are injected into every response the Server sends
+type Faults struct {
+	Delay         time.Duration // wait before answering
+	Drop          int           // ignore the next n requests
+	DropFragments []byte        // packet numbers left out when the whole list is requested
+	Reverse       bool          // send the packets of a list in reverse order
+	Duplicate     bool          // send every packet twice
+}
+
+// Server is an in-process master server listening on an ephemeral localhost port.
+// it answers PingInfoQuery packets with Master.GeneratePackets, honouring requests for a single packet number
+type Server struct {
+	*udptest.Server
+
+	Master  *protocol.Master
+	Options *protocol.Options // passed through to GeneratePackets
+
+	faults Faults
+}
+
+// NewServer starts and returns a new Server, the caller should Close it when finished
+func NewServer(master *protocol.Master) *Server {
+	s := NewUnstartedServer(master)
+	s.Start()
+
+	return s
+}
+
+// NewUnstartedServer returns a Server that doesn't listen until Start is called
+func NewUnstartedServer(master *protocol.Master) *Server {
+	if master == nil {
+		master = protocol.NewMaster()
+	}
+
+	s := &Server{
+		Master: master,
+		Options: &protocol.Options{
+			MaxServerPacketSize:  DefaultMaxServerPacketSize,
+			MaxNetworkPacketSize: protocol.MaxPacketSize,
+		},
+	}
+
+	s.Server = udptest.NewServer("mastertest", s.handle)
+
+	return s
+}
+
+func (s *Server) SetFaults(faults Faults) {
+	s.Lock()
+	s.faults = faults
+	s.Unlock()
+}
+
+func (s *Server) SetMaster(master *protocol.Master) {
+	s.Lock()
+	s.Master = master
+	s.Unlock()
+}
+
+// handle answers list requests, heartbeats and anything else are only recorded
+func (s *Server) handle(request *protocol.Packet, addr net.Addr) *udptest.Response {
+	if s.faults.Drop > 0 {
+		s.faults.Drop--
+		return nil
+	}
+
+	if request.Type != protocol.PingInfoQuery {
+		return nil
+	}
+
+	packets := s.Master.GeneratePackets(s.Options, request.Key, nil, addr)
+
+	return &udptest.Response{
+		Packets:   filterPackets(packets, request.Number, s.faults),
+		Delay:     s.faults.Delay,
+		Duplicate: s.faults.Duplicate,
+	}
+}
+
+// filterPackets applies the requested packet number and any fragment faults,
+// fragments are only dropped from requests for the whole list
+func filterPackets(packets [][]byte, number byte, faults Faults) (output [][]byte) {
+	output = make([][]byte, 0)
+
+	if number == protocol.RequestAllPackets || number == 0 {
+		for k, v := range packets {
+			if !dropFragment(faults.DropFragments, k+1) {
+				output = append(output, v)
+			}
+		}
+	} else {
+		output = append(output, protocol.RequestedPackets(packets, number)...)
+	}
+
+	if faults.Reverse {
+		for i, j := 0, len(output)-1; i < j; i, j = i+1, j-1 {
+			output[i], output[j] = output[j], output[i]
+		}
+	}
+
+	return
+}
+
+func dropFragment(fragments []byte, number int) bool {
+	for _, v := range fragments {
+		if int(v) == number {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+package mastertest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/query"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/server"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	Server  *Server
+	Options *protocol.Options
+}
+
+func (t *ServerTestSuite) SetupTest() {
+	master := protocol.NewMaster()
+	master.CommonName = "mastertest"
+	master.MOTD = "Welcome to mastertest"
+	master.MasterID = 7
+
+	for i := 0; i < 150; i++ {
+		address := fmt.Sprintf("96.126.117.%d:29001", i+1)
+		master.Servers[address], _ = server.NewServerFromString(address)
+	}
+
+	t.Server = NewServer(master)
+	t.Options = &protocol.Options{
+		Timeout:         200 * time.Millisecond,
+		FragmentRetries: -1,
+	}
+}
+
+func (t *ServerTestSuite) TearDownTest() {
+	t.Server.Close()
+}
+
+func (t *ServerTestSuite) TestServer_MasterList() {
+	q := query.NewMasterQueryWithOptions(t.Server.Address, t.Options)
+	err := q.Query()
+	t.Require().Nil(err)
+
+	t.Assert().Equal("mastertest", q.CommonName)
+	t.Assert().Equal("Welcome to mastertest", q.MOTD)
+	t.Assert().Equal(uint16(7), q.MasterID)
+	t.Assert().Len(q.Servers, 150)
+
+	requests := t.Server.Requests()
+	t.Assert().Len(requests, 1)
+	t.Assert().Equal(protocol.PingInfoQuery, requests[0].Type)
+	t.Assert().Equal(byte(protocol.RequestAllPackets), requests[0].Number)
+}
+
+func (t *ServerTestSuite) TestServer_ReverseDuplicate() {
+	t.Server.SetFaults(Faults{Reverse: true, Duplicate: true})
+
+	q := query.NewMasterQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Nil(q.Query())
+	t.Assert().Len(q.Servers, 150)
+}
+
+func (t *ServerTestSuite) TestServer_DropFragments() {
+	t.Server.SetFaults(Faults{DropFragments: []byte{2}})
+
+	q := query.NewMasterQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Contains(q.Query().Error(), "missing packet(s) 2 of 3")
+
+	// dropped fragments are still sent when asked for individually
+	t.Options.FragmentRetries = 1
+
+	q = query.NewMasterQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Nil(q.Query())
+	t.Assert().Len(q.Servers, 150)
+
+	requests := t.Server.Requests()
+	t.Assert().Equal(byte(2), requests[len(requests)-1].Number)
+}
+
+func (t *ServerTestSuite) TestServer_Drop() {
+	t.Server.SetFaults(Faults{Drop: 1})
+
+	q := query.NewMasterQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Contains(q.Query().Error(), "connection timed out")
+
+	q = query.NewMasterQueryWithOptions(t.Server.Address, t.Options)
+	t.Assert().Nil(q.Query())
+	t.Assert().Len(t.Server.Requests(), 2)
+}
+
+func (t *ServerTestSuite) TestFilterPackets_SinglePacketList() {
+	packets := [][]byte{{0x01}}
+	faults := Faults{DropFragments: []byte{1}}
+
+	t.Assert().Empty(filterPackets(packets, protocol.RequestAllPackets, faults))
+
+	// asking for packet 1 of a one packet list is still a request for a single fragment
+	t.Assert().Equal(packets, filterPackets(packets, 1, faults))
+	t.Assert().Empty(filterPackets(packets, 2, faults))
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
@@ -0,0 +1,35 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrorBadServerCount       = errors.New("server count exceeds packet length")
+	ErrorUnexpectedPacketType = errors.New("unexpected packet type")
+)
+
+// DecodeError records which packet type failed to decode and how far into the payload the decoder got.
+// the underlying cause (ErrorTruncatedPacket, ErrorBadServerCount, ...) is available through errors.Is
+type DecodeError struct {
+	Type   PacketType
+	Offset int // byte offset into Packet.Data
+	Err    error
+}
+
+func newDecodeError(t PacketType, offset int, err error) *DecodeError {
+	return &DecodeError{
+		Type:   t,
+		Offset: offset,
+		Err:    err,
+	}
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s: %s at offset %d", e.Type, e.Err, e.Offset)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,122 @@
+package protocol
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorsTestSuite struct {
+	suite.Suite
+	Packets map[string][]byte
+}
+
+func (t *ErrorsTestSuite) SetupTest() {
+	master := NewMaster()
+	master.CommonName = "Master2.Starsiege.pw"
+	master.MOTD = "Join the Starsiege Discord"
+	master.Servers = generateRemoteAddresses(12)
+
+	game := NewGameInfo()
+	game.GameName = "es3a"
+	game.Name = "DOV: City On The Edge"
+	game.GameVersion = "V 001.000r"
+
+	t.Packets = map[string][]byte{
+		"master": master.GeneratePackets(newOptions(), 0, nil, nil)[0],
+		"pinginfo": {
+			0x10, 0x04, 0xFF, 0xFD, 0x00, 0x00, 0x40, 0x00, 0x65, 0x73, 0x33, 0x61, 0x06, 0x56, 0x20, 0x30,
+			0x30, 0x31, 0x2E, 0x30, 0x30, 0x30, 0x72, 0x44, 0x4F, 0x56, 0x3A, 0x20, 0x43, 0x69, 0x74, 0x79,
+		},
+	}
+
+	t.Packets["gameinfo"], _ = game.MarshalBinary()
+}
+
+func (t *ErrorsTestSuite) unmarshalAll(data []byte) {
+	_ = NewMaster().UnmarshalBinary(data)
+	_ = new(PingInfo).UnmarshalBinary(data)
+	_ = NewGameInfo().UnmarshalBinary(data)
+	_ = NewPacket().UnmarshalBinary(data)
+}
+
+// TestErrors_Truncated_NoPanic feeds every prefix of a valid packet to every decoder
+func (t *ErrorsTestSuite) TestErrors_Truncated_NoPanic() {
+	for name, packet := range t.Packets {
+		for i := 0; i <= len(packet); i++ {
+			t.Assert().NotPanics(func() { t.unmarshalAll(packet[:i]) }, "%s truncated to %d bytes", name, i)
+		}
+	}
+}
+
+// TestErrors_Corrupted_NoPanic flips random payload bytes of valid packets
+func (t *ErrorsTestSuite) TestErrors_Corrupted_NoPanic() {
+	random := rand.New(rand.NewSource(29000))
+
+	for name, packet := range t.Packets {
+		for i := 0; i < 500; i++ {
+			corrupted := make([]byte, len(packet))
+			copy(corrupted, packet)
+
+			for j := 0; j < 4; j++ {
+				corrupted[HeaderSize+random.Intn(len(packet)-HeaderSize)] = byte(random.Intn(0x100))
+			}
+
+			t.Assert().NotPanics(func() { t.unmarshalAll(corrupted) }, "%s corrupted %x", name, corrupted)
+		}
+	}
+}
+
+func (t *ErrorsTestSuite) TestErrors_PingInfo_Truncated() {
+	err := new(PingInfo).UnmarshalBinary(t.Packets["pinginfo"][:HeaderSize+8])
+
+	decodeError := new(DecodeError)
+	t.Require().True(errors.As(err, &decodeError))
+	t.Assert().ErrorIs(err, ErrorTruncatedPacket)
+	t.Assert().Equal(PingInfoResponse, decodeError.Type)
+	t.Assert().Equal(8, decodeError.Offset)
+	t.Assert().Equal("PingInfoResponse: truncated packet at offset 8", err.Error())
+}
+
+func (t *ErrorsTestSuite) TestErrors_Master_BadServerCount() {
+	packet := t.Packets["master"]
+
+	err := NewMaster().UnmarshalBinary(packet[:len(packet)-3])
+
+	decodeError := new(DecodeError)
+	t.Require().True(errors.As(err, &decodeError))
+	t.Assert().ErrorIs(err, ErrorBadServerCount)
+	t.Assert().Equal(MasterServerList, decodeError.Type)
+
+	// the offset points at the server count byte
+	t.Assert().Equal(byte(12), packet[HeaderSize+decodeError.Offset])
+}
+
+func (t *ErrorsTestSuite) TestErrors_Master_TruncatedName() {
+	packet := t.Packets["master"]
+
+	err := NewMaster().UnmarshalBinary(packet[:HeaderSize+10])
+
+	decodeError := new(DecodeError)
+	t.Require().True(errors.As(err, &decodeError))
+	t.Assert().ErrorIs(err, ErrorTruncatedPacket)
+	t.Assert().Equal(0, decodeError.Offset)
+}
+
+func (t *ErrorsTestSuite) TestErrors_GameInfo_Truncated() {
+	packet := t.Packets["gameinfo"]
+
+	err := NewGameInfo().UnmarshalBinary(packet[:HeaderSize+12])
+
+	decodeError := new(DecodeError)
+	t.Require().True(errors.As(err, &decodeError))
+	t.Assert().ErrorIs(err, ErrorTruncatedPacket)
+	t.Assert().Equal(GameInfoResponse, decodeError.Type)
+	t.Assert().Equal(4, decodeError.Offset)
+}
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
@@ -1,7 +1,93 @@
 package protocol
 
+import (
+	"fmt"
+	"time"
+)
+
+// GameInfo is a GameInfoResponse. only the header is decoded, the compressed payload after it
+// is kept raw in Payload until its layout can be checked against a capture from a real server
 type GameInfo struct {
-	GameVersion string // es3a
-	Hostname    string // string 1
-	VersionInfo string // string 2
+	GameName    string // es3a
+	Name        string // string 1
+	GameVersion string // string 2
+	Payload     []byte // compressed payload, undecoded
+	Ping        time.Duration
+	Address     string
+	*Packet     `json:"-" csv:"-"`
+}
+
+func NewGameInfo() *GameInfo {
+	return &GameInfo{
+		Payload: make([]byte, 0),
+		Packet:  NewPacket(),
+	}
+}
+
+func (g *GameInfo) String() string {
+	return fmt.Sprintf("GameInfoResponse: %s [%s] (%s) Payload: %d bytes",
+		g.Name, g.Ping, g.GameVersion, len(g.Payload))
+}
+
+// MarshalBinary generates a GameInfoResponse packet
+// <4 byte game name><pascal server name><pascal version><payload>
+func (g *GameInfo) MarshalBinary() ([]byte, error) {
+	name, err := WritePascalString(g.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := WritePascalString(g.GameVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	gameName := make([]byte, 4)
+	copy(gameName, g.GameName)
+
+	p := NewPacket()
+	if g.Packet != nil {
+		*p = *g.Packet
+	}
+
+	p.Type = GameInfoResponse
+	p.Data = make([]byte, 0, len(gameName)+len(name)+len(version)+len(g.Payload))
+	p.Data = append(p.Data, gameName...)
+	p.Data = append(p.Data, name...)
+	p.Data = append(p.Data, version...)
+	p.Data = append(p.Data, g.Payload...)
+
+	return p.MarshalBinary()
+}
+
+func (g *GameInfo) UnmarshalBinary(data []byte) (err error) {
+	if g.Packet == nil {
+		g.Packet = NewPacket()
+	}
+
+	err = g.Packet.UnmarshalBinary(data)
+	if err != nil {
+		return
+	}
+
+	p := g.Packet
+
+	if len(p.Data) < 4 {
+		return newDecodeError(p.Type, len(p.Data), ErrorTruncatedPacket)
+	}
+
+	size := len(p.Data)
+	g.GameName, p.Data = string(p.Data[0:4]), p.Data[4:]
+
+	for _, field := range []*string{&g.Name, &g.GameVersion} {
+		*field, p.Data, err = ReadPascalStringStreamChecked(p.Data)
+		if err != nil {
+			return newDecodeError(p.Type, size-len(p.Data), err)
+		}
+	}
+
+	g.Payload = make([]byte, len(p.Data))
+	copy(g.Payload, p.Data)
+
+	return nil
 }
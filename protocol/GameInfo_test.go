@@ -0,0 +1,70 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type GameInfoTestSuite struct {
+	suite.Suite
+	GameInfo *GameInfo
+}
+
+func (t *GameInfoTestSuite) SetupTest() {
+	t.GameInfo = NewGameInfo()
+	t.GameInfo.Packet.Number = RequestAllPackets
+	t.GameInfo.Packet.Key = 0x0102
+	t.GameInfo.GameName = "es3a"
+	t.GameInfo.Name = "DOV: City On The Edge"
+	t.GameInfo.GameVersion = "V 001.000r"
+	t.GameInfo.Payload = []byte{0xc0, 0x10, 0x43, 0x69, 0x74, 0x79, 0x00}
+}
+
+func (t *GameInfoTestSuite) TestGameInfo_MarshalBinary_Header() {
+	output, err := t.GameInfo.MarshalBinary()
+	t.Assert().Nil(err)
+
+	question := []byte{
+		0x10, 0x08, 0xFF, 0x00, 0x01, 0x02, 0x00, 0x00, 0x65, 0x73, 0x33, 0x61, 0x15,
+	}
+
+	t.Assert().Equal(question, output[:len(question)])
+	t.Assert().Equal(t.GameInfo.Payload, output[len(output)-len(t.GameInfo.Payload):])
+}
+
+func (t *GameInfoTestSuite) TestGameInfo_RoundTrip() {
+	output, err := t.GameInfo.MarshalBinary()
+	t.Assert().Nil(err)
+
+	response := new(GameInfo)
+
+	err = response.UnmarshalBinary(output)
+	t.Assert().Nil(err)
+
+	t.Assert().Equal(GameInfoResponse, response.Type)
+	t.Assert().Equal(t.GameInfo.Key, response.Key)
+	t.Assert().Equal(t.GameInfo.GameName, response.GameName)
+	t.Assert().Equal(t.GameInfo.Name, response.Name)
+	t.Assert().Equal(t.GameInfo.GameVersion, response.GameVersion)
+	t.Assert().Equal(t.GameInfo.Payload, response.Payload)
+}
+
+func (t *GameInfoTestSuite) TestGameInfo_UnmarshalBinary_Truncated() {
+	output, err := t.GameInfo.MarshalBinary()
+	t.Assert().Nil(err)
+
+	err = NewGameInfo().UnmarshalBinary(output[:HeaderSize+6])
+	t.Assert().ErrorIs(err, ErrorTruncatedPacket)
+
+	// the payload isn't decoded, so cutting it short isn't an error
+	response := NewGameInfo()
+
+	err = response.UnmarshalBinary(output[:len(output)-4])
+	t.Assert().Nil(err)
+	t.Assert().Equal(t.GameInfo.Payload[:3], response.Payload)
+}
+
+func TestGameInfoTestSuite(t *testing.T) {
+	suite.Run(t, new(GameInfoTestSuite))
+}
@@ -0,0 +1,68 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// Heartbeat is sent by a game server to register itself with a master server.
+// the master answers by verifying the sender with a PingInfoQuery before listing it
+type Heartbeat struct {
+	*Packet `json:"-" csv:"-"`
+	Address string
+	Payload []byte // anything following the header, starsiege servers normally send nothing
+}
+
+func NewHeartbeat() *Heartbeat {
+	output := &Heartbeat{
+		Packet:  NewPacket(),
+		Payload: make([]byte, 0),
+	}
+
+	output.Type = MasterServerHeartbeat
+	output.Number = RequestAllPackets
+
+	return output
+}
+
+func NewHeartbeatWithData(data []byte) (out *Heartbeat, err error) {
+	out = NewHeartbeat()
+	err = out.UnmarshalBinary(data)
+
+	return
+}
+
+func (h *Heartbeat) String() string {
+	return fmt.Sprintf("Heartbeat: %s key: %d id: %d payload: %x", h.Address, h.Key, h.ID, h.Payload)
+}
+
+func (h *Heartbeat) MarshalBinary() ([]byte, error) {
+	p := NewPacket()
+	if h.Packet != nil {
+		*p = *h.Packet
+	}
+
+	p.Type = MasterServerHeartbeat
+	p.Data = h.Payload
+
+	return p.MarshalBinary()
+}
+
+func (h *Heartbeat) UnmarshalBinary(data []byte) error {
+	if h.Packet == nil {
+		h.Packet = NewPacket()
+	}
+
+	err := h.Packet.UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+
+	if h.Type != MasterServerHeartbeat {
+		return newDecodeError(h.Type, 0, ErrorUnexpectedPacketType)
+	}
+
+	h.Payload = make([]byte, len(h.Data))
+	copy(h.Payload, h.Data)
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HeartbeatTestSuite struct {
+	suite.Suite
+	Heartbeat *Heartbeat
+}
+
+func (t *HeartbeatTestSuite) SetupTest() {
+	t.Heartbeat = NewHeartbeat()
+}
+
+func (t *HeartbeatTestSuite) TestHeartbeat_MarshalBinary() {
+	t.Heartbeat.Key = 0x02
+
+	question := []byte{
+		0x10, 0x05, 0xff, 0x00, 0x00, 0x02, 0x00, 0x00,
+	}
+
+	output, err := t.Heartbeat.MarshalBinary()
+	t.Assert().Nil(err)
+	t.Assert().Equal(question, output)
+}
+
+func (t *HeartbeatTestSuite) TestHeartbeat_UnmarshalBinary() {
+	question := []byte{
+		0x10, 0x05, 0xff, 0x00, 0x12, 0x34, 0x00, 0x63,
+	}
+
+	err := t.Heartbeat.UnmarshalBinary(question)
+	t.Assert().Nil(err)
+
+	t.Assert().Equal(byte(Version), t.Heartbeat.Version)
+	t.Assert().Equal(MasterServerHeartbeat, t.Heartbeat.Type)
+	t.Assert().Equal(byte(RequestAllPackets), t.Heartbeat.Number)
+	t.Assert().Equal(byte(0x00), t.Heartbeat.Total)
+	t.Assert().Equal(uint16(0x1234), t.Heartbeat.Key)
+	t.Assert().Equal(uint16(0x63), t.Heartbeat.ID)
+	t.Assert().Equal([]byte{}, t.Heartbeat.Payload)
+}
+
+func (t *HeartbeatTestSuite) TestHeartbeat_RoundTrip_Payload() {
+	question := []byte{
+		0x10, 0x05, 0x01, 0x02, 0xAB, 0xCD, 0x01, 0x00, 0x65, 0x73, 0x33, 0x61, 0x00,
+	}
+
+	heartbeat, err := NewHeartbeatWithData(question)
+	t.Assert().Nil(err)
+	t.Assert().Equal([]byte{0x65, 0x73, 0x33, 0x61, 0x00}, heartbeat.Payload)
+
+	output, err := heartbeat.MarshalBinary()
+	t.Assert().Nil(err)
+	t.Assert().Equal(question, output)
+}
+
+func (t *HeartbeatTestSuite) TestHeartbeat_UnmarshalBinary_WrongType() {
+	question := []byte{
+		0x10, 0x03, 0xff, 0x00, 0x00, 0x02, 0x00, 0x00,
+	}
+
+	err := t.Heartbeat.UnmarshalBinary(question)
+	t.Assert().ErrorIs(err, ErrorUnexpectedPacketType)
+}
+
+func (t *HeartbeatTestSuite) TestHeartbeat_UnmarshalBinary_Truncated() {
+	err := t.Heartbeat.UnmarshalBinary([]byte{0x10, 0x05, 0xff})
+	t.Assert().ErrorIs(err, ErrorTruncatedPacket)
+}
+
+func TestHeartbeatTestSuite(t *testing.T) {
+	suite.Run(t, new(HeartbeatTestSuite))
+}
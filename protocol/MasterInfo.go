@@ -50,6 +50,14 @@ func (m *Master) UnmarshalBinarySet(data [][]byte) (err error) {
 }
 
 func (m *Master) UnmarshalBinary(data []byte) (err error) {
+	if m.Packet == nil {
+		m.Packet = NewPacket()
+	}
+
+	if m.Servers == nil {
+		m.Servers = make(map[string]*server.Server)
+	}
+
 	err = m.Packet.UnmarshalBinary(data)
 	if err != nil {
 		return
@@ -62,12 +70,23 @@ func (m *Master) UnmarshalBinary(data []byte) (err error) {
 		return
 	}
 
+	// track how far into the payload we are for error reporting
+	size := len(p.Data)
+
 	// if it's the first packet (and only the first packet)
 	// parse out the common name and MOTD
 	if p.Number == 1 {
-		m.CommonName, p.Data = ReadPascalStringStream(p.Data)
+		m.CommonName, p.Data, err = ReadPascalStringStreamChecked(p.Data)
+		if err != nil {
+			return newDecodeError(p.Type, size-len(p.Data), err)
+		}
+
 		m.CommonName = strings.ReplaceAll(m.CommonName, `\n`, "")
-		m.MOTD, p.Data = ReadPascalStringStream(p.Data)
+
+		m.MOTD, p.Data, err = ReadPascalStringStreamChecked(p.Data)
+		if err != nil {
+			return newDecodeError(p.Type, size-len(p.Data), err)
+		}
 
 		m.MOTD = strings.ReplaceAll(m.MOTD, `\n`, " ")
 		if len(m.MOTD) > 10 {
@@ -80,7 +99,16 @@ func (m *Master) UnmarshalBinary(data []byte) (err error) {
 		return
 	}
 
-	p.Data = p.Data[1:] // null header separator
+	// the first packet has a null separator between the header and the server count,
+	// overflow packets start directly with the server count
+	if p.Number <= 1 {
+		p.Data = p.Data[1:] // null header separator
+	}
+
+	if len(p.Data) == 0 {
+		return
+	}
+
 	serverCount := byte(0)
 	serverCount, p.Data = p.Data[0], p.Data[1:]
 
@@ -88,6 +116,11 @@ func (m *Master) UnmarshalBinary(data []byte) (err error) {
 		return
 	}
 
+	// <0x06><4 bytes ipv4 addr><2 bytes port> per entry
+	if len(p.Data) < int(serverCount)*7 {
+		return newDecodeError(p.Type, size-len(p.Data)-1, ErrorBadServerCount)
+	}
+
 	for i := byte(0); i < serverCount; i++ {
 		p.Data = p.Data[1:] // skip separator byte "0x6"
 
@@ -344,3 +377,18 @@ func (m *Master) GeneratePackets(options *Options, key uint16, laddr net.Addr, r
 
 	return output
 }
+
+// RequestedPackets narrows the output of GeneratePackets down to the packet number a client asked for.
+// RequestAllPackets and 0, which clients leaving the number unset send, return every packet.
+// a number outside the set returns none
+func RequestedPackets(packets [][]byte, number byte) [][]byte {
+	if number == RequestAllPackets || number == 0 {
+		return packets
+	}
+
+	if number < 1 || int(number) > len(packets) {
+		return [][]byte{}
+	}
+
+	return packets[number-1 : number]
+}
@@ -321,6 +321,72 @@ func (t MasterTestSite) TestMaster_GeneratePackets_Replace_LocalNets_Adjacent()
 	t.AssertSetEqual(response, packets)
 }
 
+// TestMaster_UnmarshalBinarySet_Spanned tests if we can merge
+// every packet of a spanned response back into a single master
+func (t MasterTestSite) TestMaster_UnmarshalBinarySet_Spanned() {
+	t.Master.Servers = generateRemoteAddresses(300)
+
+	options := newOptions()
+	packets := t.Master.GeneratePackets(options, 0x0300, nil, nil)
+	t.Assert().Greater(len(packets), 2)
+
+	m := NewMaster()
+	err := m.UnmarshalBinarySet(packets)
+	t.Assert().Nil(err)
+
+	t.Assert().Equal("Dummythicc Masterserver Testing", m.CommonName)
+	t.Assert().Equal(t.Master.MOTD, m.MOTD)
+	t.Assert().Len(m.Servers, len(t.Master.Servers))
+
+	for k := range t.Master.Servers {
+		t.Assert().Contains(m.Servers, k)
+	}
+}
+
+// TestMaster_UnmarshalBinary_Overflow_TrailingByte tests that an overflow packet is decoded
+// by its position in the list, whatever length its payload happens to be
+func (t MasterTestSite) TestMaster_UnmarshalBinary_Overflow_TrailingByte() {
+	t.Master.Servers = generateRemoteAddresses(300)
+
+	packets := t.Master.GeneratePackets(newOptions(), 0x0300, nil, nil)
+	t.Require().Greater(len(packets), 1)
+
+	expected := NewMaster()
+	t.Require().Nil(expected.UnmarshalBinary(packets[1]))
+	t.Require().NotEmpty(expected.Servers)
+
+	// <server count><7 bytes per server> plus one byte is 2 mod 7, the same as a first packet's payload
+	padded := append(append([]byte{}, packets[1]...), 0x00)
+	t.Require().Equal(2, (len(padded)-HeaderSize)%7)
+
+	m := NewMaster()
+	t.Assert().Nil(m.UnmarshalBinary(padded))
+	t.Assert().Len(m.Servers, len(expected.Servers))
+
+	for k := range expected.Servers {
+		t.Assert().Contains(m.Servers, k)
+	}
+}
+
+// TestMaster_RequestedPackets tests if a single packet can be picked out of a spanned response
+func (t MasterTestSite) TestMaster_RequestedPackets() {
+	packets := [][]byte{{0x01}, {0x02}, {0x03}}
+
+	t.Assert().Equal(packets, RequestedPackets(packets, RequestAllPackets))
+	t.Assert().Equal(packets, RequestedPackets(packets, 0))
+	t.Assert().Equal([][]byte{{0x02}}, RequestedPackets(packets, 2))
+	t.Assert().Equal([][]byte{{0x03}}, RequestedPackets(packets, 3))
+}
+
+// TestMaster_RequestedPackets_OutOfRange tests that a re-request for a packet that doesn't exist
+// doesn't send the whole list again
+func (t MasterTestSite) TestMaster_RequestedPackets_OutOfRange() {
+	packets := [][]byte{{0x01}, {0x02}, {0x03}}
+
+	t.Assert().Empty(RequestedPackets(packets, 4))
+	t.Assert().Empty(RequestedPackets([][]byte{}, 1))
+}
+
 /********************************************************************/
 // Utility Functions
 
@@ -343,6 +409,18 @@ func generateAddresses(count int) (output map[string]*server.Server) {
 	return
 }
 
+func generateRemoteAddresses(count int) (output map[string]*server.Server) {
+	output = make(map[string]*server.Server)
+
+	for i := 0; i < count; i++ {
+		addrPort := fmt.Sprintf("96.126.%d.%d:%d", i/250, i%250+1, 29001+i%8)
+		thisServer, _ := server.NewServerFromString(addrPort)
+		output[addrPort] = thisServer
+	}
+
+	return
+}
+
 func newOptions() *Options {
 	_, localhost, _ := net.ParseCIDR("127.0.0.1/8")
 	_, localnet1, _ := net.ParseCIDR("172.16.0.1/16")
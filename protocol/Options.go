@@ -11,6 +11,19 @@ type Options struct {
 	ExternalIP    net.IP
 	Debug         bool
 
+	// number of times missing master list packets are re-requested, 0 uses the default, negative disables
+	FragmentRetries int
+
+	// retry policy for game and master queries, the whole query is resent when an attempt fails
+	Attempts       int           // total attempts per query, 0 or 1 only sends once
+	AttemptTimeout time.Duration // time each attempt waits for a response, 0 uses Timeout
+	Backoff        time.Duration // wait before the second attempt, doubled for every attempt after that
+	BackoffJitter  float64       // randomly varies each backoff by up to this fraction of it, 0 to 1
+
+	// fan out limits for darkstar.Query.Servers and darkstar.Query.Masters, 0 is unlimited
+	MaxInFlight      int // most queries outstanding at once
+	PacketsPerSecond int // rate new queries are sent at, retries and fragment requests aren't paced
+
 	MaxServerPacketSize  uint16
 	MaxNetworkPacketSize uint16
 }
@@ -1,11 +1,12 @@
 package protocol
 
 import (
-	"bytes"
+	"crypto/rand"
 	"encoding"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	mathrand "math/rand"
 )
 
 const Version = 0x10
@@ -16,6 +17,7 @@ const MaxDataSize = MaxPacketSize - HeaderSize
 
 var ErrorUnknownPacketVersion = errors.New("unknown packet version")
 var ErrorEmptyPacket = errors.New("empty packet received")
+var ErrorTruncatedPacket = errors.New("truncated packet")
 
 type Packet struct {
 	Version byte
@@ -38,6 +40,19 @@ func NewPacket() *Packet {
 	}
 }
 
+// RandomKey returns a random starting transaction key so replies to earlier or other clients' packets
+// are unlikely to match
+func RandomKey() uint16 {
+	buf := make([]byte, 2)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return uint16(mathrand.Intn(0x10000))
+	}
+
+	return binary.BigEndian.Uint16(buf)
+}
+
 func NewPacketWithData(data []byte) (out *Packet, err error) {
 	out = NewPacket()
 	err = out.UnmarshalBinary(data)
@@ -58,26 +73,23 @@ func (p *Packet) MarshalBinary() ([]byte, error) {
 	return out, nil
 }
 
+// UnmarshalBinary parses exactly the bytes given, callers should pass only the length read from the socket
+// payloads are allowed to begin or end with null bytes
 func (p *Packet) UnmarshalBinary(data []byte) error {
-	data = bytes.Trim(data, "\x00")
-
-	if len(data) < 0 {
+	if len(data) == 0 {
 		return ErrorEmptyPacket
 	}
 
-	// pad data to a minimum of 8 bytes
-	if len(data) < 8 {
-		tmp := make([]byte, 8)
-		copy(tmp, data)
-		data = tmp
-	}
-
-	p.Data = make([]byte, len(data)-HeaderSize)
-
 	if data[0] != Version && data[0] != VersionExt {
 		return ErrorUnknownPacketVersion
 	}
 
+	if len(data) < HeaderSize {
+		return ErrorTruncatedPacket
+	}
+
+	p.Data = make([]byte, len(data)-HeaderSize)
+
 	p.Version = data[0]
 	p.Type = PacketType(data[1])
 	p.Number = data[2]
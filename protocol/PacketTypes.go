@@ -1,5 +1,7 @@
 package protocol
 
+import "fmt"
+
 type PacketType int
 
 const RequestAllPackets = 0xff
@@ -23,5 +25,9 @@ var packetTypeString = map[PacketType]string{
 }
 
 func (p PacketType) String() string {
-	return packetTypeString[PacketType(int(p)-int(PingInfoQuery))]
+	if s, ok := packetTypeString[p]; ok {
+		return s
+	}
+
+	return fmt.Sprintf("PacketType(0x%02x)", int(p))
 }
@@ -41,7 +41,7 @@ func (t *Protocol_PacketTestSuite) TestProtocol_UnmarshalBinary() {
 	t.Assert().Equal(question[8:], t.Packet.Data)
 }
 
-func (t *Protocol_PacketTestSuite) TestProtocol_UnmarshalBinary_TrimNull() {
+func (t *Protocol_PacketTestSuite) TestProtocol_UnmarshalBinary_TrailingNull() {
 	question := []byte{
 		0x10, 0x06, 0x04, 0x01, 0x45, 0x00, 0x00, 0x02, 0x00, 0x00,
 	}
@@ -55,6 +55,19 @@ func (t *Protocol_PacketTestSuite) TestProtocol_UnmarshalBinary_TrimNull() {
 	t.Assert().Equal(question[3], t.Packet.Total)
 	t.Assert().Equal(binary.BigEndian.Uint16(question[4:4+2]), t.Packet.Key)
 	t.Assert().Equal(binary.BigEndian.Uint16(question[6:6+2]), t.Packet.ID)
+	t.Assert().Equal([]byte{0x00, 0x00}, t.Packet.Data)
+}
+
+func (t *Protocol_PacketTestSuite) TestProtocol_UnmarshalBinary_HeaderOnly() {
+	question := []byte{
+		0x10, 0x06, 0x04, 0x01, 0x45, 0x00, 0x00, 0x00,
+	}
+
+	err := t.Packet.UnmarshalBinary(question)
+	t.Assert().Nil(err)
+
+	t.Assert().Equal(uint16(0x4500), t.Packet.Key)
+	t.Assert().Equal(uint16(0), t.Packet.ID)
 	t.Assert().Equal([]byte{}, t.Packet.Data)
 }
 
@@ -63,16 +76,20 @@ func (t *Protocol_PacketTestSuite) TestProtocol_UnmarshalBinary_SmallPacket() {
 		0x10, 0x06, 0x04, 0x01, 0x45, 0x00,
 	}
 
+	err := t.Packet.UnmarshalBinary(question)
+	t.Assert().ErrorIs(err, ErrorTruncatedPacket)
+}
+
+func (t *Protocol_PacketTestSuite) TestProtocol_UnmarshalBinary_LeadingNullPayload() {
+	// port 29440 little endian is <0x00><0x73>
+	question := []byte{
+		0x10, 0x06, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x73, 0x00,
+	}
+
 	err := t.Packet.UnmarshalBinary(question)
 	t.Assert().Nil(err)
 
-	t.Assert().Equal(question[0], t.Packet.Version)
-	t.Assert().Equal(question[1], byte(t.Packet.Type))
-	t.Assert().Equal(question[2], t.Packet.Number)
-	t.Assert().Equal(question[3], t.Packet.Total)
-	t.Assert().Equal(binary.BigEndian.Uint16(question[4:4+2]), t.Packet.Key)
-	t.Assert().Equal(uint16(0), t.Packet.ID)
-	t.Assert().Equal([]byte{}, t.Packet.Data)
+	t.Assert().Equal([]byte{0x00, 0x73, 0x00}, t.Packet.Data)
 }
 
 func (t *Protocol_PacketTestSuite) TestProtocol_MarshalBinary() {
@@ -93,6 +110,17 @@ func (t *Protocol_PacketTestSuite) TestProtocol_MarshalBinary() {
 	t.Assert().Equal(question, output)
 }
 
+func (t *Protocol_PacketTestSuite) TestProtocol_UnmarshalBinary_Empty() {
+	err := t.Packet.UnmarshalBinary([]byte{})
+	t.Assert().ErrorIs(err, ErrorEmptyPacket)
+}
+
+func (t *Protocol_PacketTestSuite) TestProtocol_PacketTypeString() {
+	t.Assert().Equal("PingInfoQuery", PingInfoQuery.String())
+	t.Assert().Equal("GameInfoResponse", GameInfoResponse.String())
+	t.Assert().Equal("PacketType(0x42)", PacketType(0x42).String())
+}
+
 func TestProtocol_PacketTestSuite(t *testing.T) {
 	suite.Run(t, new(Protocol_PacketTestSuite))
 }
@@ -7,6 +7,11 @@ import (
 	"time"
 )
 
+const (
+	pingInfoGameNameSize    = 4
+	pingInfoGameVersionSize = 10
+)
+
 type PingInfo struct {
 	GameMode    byte          `csv:"-"` // ??
 	PlayerCount byte          `csv:"cur_players"`
@@ -25,25 +30,52 @@ func (s *PingInfo) String() string {
 		string(s.Name), s.Ping, s.GameStatus, s.PlayerCount, s.MaxPlayers)
 }
 
+// MarshalBinary generates a PingInfoResponse packet
+// the header carries the game mode in Total and the player counts in ID <max players><player count>
+// <4 byte game name><status byte><10 byte game version><null terminated name>
 func (s *PingInfo) MarshalBinary() ([]byte, error) {
-	// TODO
-	return s.Packet.MarshalBinary()
+	p := NewPacket()
+	if s.Packet != nil {
+		*p = *s.Packet
+	}
+
+	p.Type = PingInfoResponse
+	p.Total = s.GameMode
+	p.ID = uint16(s.MaxPlayers)<<8 | uint16(s.PlayerCount)
+
+	p.Data = make([]byte, pingInfoGameNameSize+1+pingInfoGameVersionSize, pingInfoGameNameSize+1+pingInfoGameVersionSize+len(s.Name)+1)
+	copy(p.Data[0:pingInfoGameNameSize], s.GameName)
+	p.Data[pingInfoGameNameSize] = byte(s.GameStatus)
+	copy(p.Data[pingInfoGameNameSize+1:], s.GameVersion)
+	p.Data = append(p.Data, WriteCString(string(s.Name))...)
+
+	return p.MarshalBinary()
 }
 
 func (s *PingInfo) UnmarshalBinary(data []byte) error {
+	if s.Packet == nil {
+		s.Packet = NewPacket()
+	}
+
 	err := s.Packet.UnmarshalBinary(data)
 	if err != nil {
 		return err
 	}
 
 	p := s.Packet
+
+	// <4 byte game name><status byte><10 byte game version>
+	if len(p.Data) < pingInfoGameNameSize+1+pingInfoGameVersionSize {
+		return newDecodeError(p.Type, len(p.Data), ErrorTruncatedPacket)
+	}
+
 	s.GameMode = p.Total
 	s.PlayerCount = byte(p.ID & math.MaxUint8)
 	s.MaxPlayers = byte((p.ID >> 8) & math.MaxUint8)
 
-	s.GameName, p.Data = p.Data[0:4], p.Data[4:]
+	s.GameName, p.Data = p.Data[0:pingInfoGameNameSize], p.Data[pingInfoGameNameSize:]
 	s.GameStatus, p.Data = StatusByte(p.Data[0]), p.Data[1:]
-	s.GameVersion, p.Data = p.Data[0:10], p.Data[10:]
+	s.GameVersion, p.Data = p.Data[0:pingInfoGameVersionSize], p.Data[pingInfoGameVersionSize:]
 
 	s.Name = p.Data[:Clen(p.Data)]
 
@@ -49,6 +49,84 @@ func (t *PingInfoTestSuite) TestServer_PingInfoUnmarshal() {
 	t.Assert().Equal(response, t.PingInfo)
 }
 
+func (t *PingInfoTestSuite) TestServer_PingInfoMarshal() {
+	t.PingInfo = &PingInfo{
+		Packet: &Packet{
+			Version: Version,
+			Number:  0xff,
+			Key:     0x0102,
+		},
+		GameMode:    0xfd,
+		GameName:    []byte("es3a"),
+		GameVersion: []byte("V 001.000r"),
+		GameStatus:  0x6,
+		PlayerCount: 0x3,
+		MaxPlayers:  0x40,
+		Name:        []byte("DOV: City On The"),
+	}
+
+	response := []byte{
+		0x10, 0x04, 0xFF, 0xFD, 0x01, 0x02, 0x40, 0x03, 0x65, 0x73, 0x33, 0x61, 0x06, 0x56, 0x20, 0x30,
+		0x30, 0x31, 0x2E, 0x30, 0x30, 0x30, 0x72, 0x44, 0x4F, 0x56, 0x3A, 0x20, 0x43, 0x69, 0x74, 0x79,
+		0x20, 0x4F, 0x6E, 0x20, 0x54, 0x68, 0x65, 0x00,
+	}
+
+	output, err := t.PingInfo.MarshalBinary()
+	t.Assert().Nil(err)
+
+	t.Assert().Equal(response, output)
+}
+
+func (t *PingInfoTestSuite) TestServer_PingInfoRoundTrip() {
+	question := []byte{
+		0x10, 0x04, 0xFF, 0xFD, 0x00, 0x07, 0x40, 0x05, 0x65, 0x73, 0x33, 0x61, 0x06, 0x56, 0x20, 0x30,
+		0x30, 0x31, 0x2E, 0x30, 0x30, 0x30, 0x72, 0x44, 0x4F, 0x56, 0x3A, 0x20, 0x43, 0x69, 0x74, 0x79,
+		0x20, 0x4F, 0x6E, 0x20, 0x54, 0x68, 0x65, 0x00,
+	}
+
+	err := t.PingInfo.UnmarshalBinary(question)
+	t.Assert().Nil(err)
+
+	output, err := t.PingInfo.MarshalBinary()
+	t.Assert().Nil(err)
+
+	t.Assert().Equal(question, output)
+}
+
+func (t *PingInfoTestSuite) TestServer_PingInfoRoundTrip_Empty() {
+	question := []byte{
+		0x10, 0x04, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x65, 0x73, 0x33, 0x61, 0x00, 0x56, 0x20, 0x31,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+
+	err := t.PingInfo.UnmarshalBinary(question)
+	t.Assert().Nil(err)
+	t.Assert().Equal([]byte{}, t.PingInfo.Name)
+
+	output, err := t.PingInfo.MarshalBinary()
+	t.Assert().Nil(err)
+
+	t.Assert().Equal(question, output)
+}
+
+func (t *PingInfoTestSuite) TestServer_PingInfoMarshal_NoPacket() {
+	t.PingInfo = &PingInfo{
+		GameName:    []byte("es3a"),
+		GameVersion: []byte("V 1"),
+		MaxPlayers:  0x10,
+	}
+
+	response := []byte{
+		0x10, 0x04, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x65, 0x73, 0x33, 0x61, 0x00, 0x56, 0x20, 0x31,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+
+	output, err := t.PingInfo.MarshalBinary()
+	t.Assert().Nil(err)
+
+	t.Assert().Equal(response, output)
+}
+
 func TestPingInfoTestSuite(t *testing.T) {
 	suite.Run(t, new(PingInfoTestSuite))
 }
@@ -78,6 +78,11 @@ func (s StatusByteStruct) MarshalBinary() (output byte) {
 	return
 }
 
+// Has reports whether bit is set
+func (s StatusByte) Has(bit StatusBit) bool {
+	return int(s)&int(bit) != 0
+}
+
 func (s StatusByte) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.Struct())
 }
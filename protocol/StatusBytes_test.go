@@ -121,6 +121,15 @@ func (t *StatusBytesTestSuite) TestMarshalByte_None() {
 	t.Assert().Equal(question, output)
 }
 
+func (t *StatusBytesTestSuite) TestHas() {
+	t.byte = StatusByte(Dedicated | Started)
+
+	t.Assert().True(t.byte.Has(Dedicated))
+	t.Assert().True(t.byte.Has(Started))
+	t.Assert().False(t.byte.Has(Protected))
+	t.Assert().False(t.byte.Has(AllowOldClients))
+}
+
 func TestStatusByte_StatusBytesTestSuite(t *testing.T) {
 	suite.Run(t, new(StatusBytesTestSuite))
 }
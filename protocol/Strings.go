@@ -37,30 +37,41 @@ func ReadCString(input []byte) string {
 	return string(output)
 }
 
+// ReadPascalStringStream reads a pascal string and returns it along with the rest of the input.
+// a length running past the end of the input is clamped, use ReadPascalStringStreamChecked to detect it
 func ReadPascalStringStream(input []byte) (string, []byte) {
 	if len(input) <= 0 {
 		return "", input
 	}
 
-	length, input := input[0], input[1:]
+	length, input := int(input[0]), input[1:]
 	if length <= 0 || len(input) <= 0 {
 		return "", input
 	}
 
+	if length > len(input) {
+		length = len(input)
+	}
+
 	return string(input[0:length]), input[length:]
 }
 
-func ReadPascalString(input []byte) string {
-	if len(input) <= 0 {
-		return ""
+// ReadPascalStringStreamChecked is ReadPascalStringStream, returning ErrorTruncatedPacket
+// instead of clamping when the length byte runs past the end of the input
+func ReadPascalStringStreamChecked(input []byte) (string, []byte, error) {
+	if len(input) <= 0 || int(input[0]) > len(input)-1 {
+		return "", input, ErrorTruncatedPacket
 	}
 
-	length, input := input[0], input[1:]
-	if length <= 0 || len(input) <= 0 {
-		return ""
-	}
+	output, input := ReadPascalStringStream(input)
+
+	return output, input, nil
+}
+
+func ReadPascalString(input []byte) string {
+	output, _ := ReadPascalStringStream(input)
 
-	return string(input[0:length])
+	return output
 }
 
 func WritePascalString(input string) ([]byte, error) {
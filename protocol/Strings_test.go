@@ -178,6 +178,37 @@ func (t Protocol_StringTestSuite) TestProtocol_ReadPascalStringStream_Empty() {
 	t.Assert().Equal(remainder, remainder_output)
 }
 
+func (t Protocol_StringTestSuite) TestProtocol_ReadPascalStringStream_Overrun() {
+	question := []byte{0x2C, 0x54, 0x68, 0x65}
+
+	output, remainder := ReadPascalStringStream(question)
+	t.Assert().Equal("The", output)
+	t.Assert().Equal([]byte{}, remainder)
+
+	t.Assert().Equal("The", ReadPascalString(question))
+}
+
+func (t Protocol_StringTestSuite) TestProtocol_ReadPascalStringStreamChecked() {
+	question := []byte{0x03, 0x54, 0x68, 0x65, 0x01}
+
+	output, remainder, err := ReadPascalStringStreamChecked(question)
+	t.Assert().Nil(err)
+	t.Assert().Equal("The", output)
+	t.Assert().Equal([]byte{0x01}, remainder)
+}
+
+func (t Protocol_StringTestSuite) TestProtocol_ReadPascalStringStreamChecked_Overrun() {
+	question := []byte{0x2C, 0x54, 0x68, 0x65}
+
+	output, remainder, err := ReadPascalStringStreamChecked(question)
+	t.Assert().ErrorIs(err, ErrorTruncatedPacket)
+	t.Assert().Equal("", output)
+	t.Assert().Equal(question, remainder)
+
+	_, _, err = ReadPascalStringStreamChecked([]byte{})
+	t.Assert().ErrorIs(err, ErrorTruncatedPacket)
+}
+
 func (t Protocol_StringTestSuite) TestProtocol_WritePascalString() {
 	question := "The quick brown fox jumps over the lazy dog."
 	response := []byte{
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// deadline returns the earlier of now + timeout and the context deadline
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	output := time.Now().Add(timeout)
+
+	if d, ok := ctx.Deadline(); ok && d.Before(output) {
+		return d
+	}
+
+	return output
+}
+
+// setDeadline extends the connection deadline, returning the context error if it was cancelled.
+// ctx is checked after the deadline is set so a cancellation can't be overwritten by it
+func setDeadline(ctx context.Context, conn net.Conn, timeout time.Duration) error {
+	err := conn.SetDeadline(deadline(ctx, timeout))
+	if err != nil {
+		return err
+	}
+
+	return contextError(ctx)
+}
+
+// watchContext unblocks any pending read or write on conn once ctx is done,
+// the returned stop function must be called once the connection is finished with
+func watchContext(ctx context.Context, conn net.Conn) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// contextError returns ctx.Err(), or context.DeadlineExceeded once the context deadline has passed.
+// a read timing out on the context deadline can return before the context's own timer fires
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := ctx.Deadline(); ok && !time.Now().Before(d) {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// ContextError is ctx.Err(), also returning context.DeadlineExceeded once the deadline has passed
+// but before the context's own timer has fired, when a query may already have timed out on it
+func ContextError(ctx context.Context) error {
+	return contextError(ctx)
+}
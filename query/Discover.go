@@ -0,0 +1,187 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// LocalNetworks returns the address and mask of every interface on this machine
+func LocalNetworks() (output []*net.IPNet) {
+	output = make([]*net.IPNet, 0)
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return
+	}
+
+	for _, i := range ifaces {
+		addrs, err := i.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for k := range addrs {
+			if network, ok := addrs[k].(*net.IPNet); ok {
+				output = append(output, network)
+			}
+		}
+	}
+
+	return
+}
+
+// broadcastAddresses returns the directed broadcast address of every ipv4 network that has one,
+// loopback and point to point networks are left out
+func broadcastAddresses(networks []*net.IPNet) (output []net.IP) {
+	output = make([]net.IP, 0)
+	seen := make(map[string]bool)
+
+	for _, v := range networks {
+		ip := v.IP.To4()
+		if ip == nil || ip.IsLoopback() || len(v.Mask) != net.IPv4len {
+			continue
+		}
+
+		if ones, _ := v.Mask.Size(); ones >= 31 {
+			continue
+		}
+
+		broadcast := make(net.IP, net.IPv4len)
+		for k := range ip {
+			broadcast[k] = ip[k] | ^v.Mask[k]
+		}
+
+		if !seen[broadcast.String()] {
+			seen[broadcast.String()] = true
+			output = append(output, broadcast)
+		}
+	}
+
+	return
+}
+
+// Discover broadcasts a PingInfoQuery to every port from firstPort to lastPort on each local network,
+// returning every game server that answers before options.Timeout, in the order they answered.
+// the servers found so far are returned along with the error if ctx is done first
+func Discover(ctx context.Context, options *protocol.Options, firstPort int, lastPort int) (output []*PingInfoQuery, err error) {
+	if firstPort < 1 || lastPort > 65535 || firstPort > lastPort {
+		return make([]*PingInfoQuery, 0), fmt.Errorf("%w: %d-%d", ErrorPortRange, firstPort, lastPort)
+	}
+
+	broadcasts := broadcastAddresses(LocalNetworks())
+	if len(broadcasts) == 0 {
+		return make([]*PingInfoQuery, 0), ErrorNoNetworks
+	}
+
+	targets := make([]string, 0, len(broadcasts)*(lastPort-firstPort+1))
+	for _, ip := range broadcasts {
+		for port := firstPort; port <= lastPort; port++ {
+			targets = append(targets, net.JoinHostPort(ip.String(), strconv.Itoa(port)))
+		}
+	}
+
+	return discover(ctx, options, targets)
+}
+
+// discover sends one PingInfoQuery to every target from a single socket and collects the answers until the deadline
+func discover(ctx context.Context, options *protocol.Options, targets []string) (output []*PingInfoQuery, err error) {
+	output = make([]*PingInfoQuery, 0)
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return output, connectionError(options, "unable to listen for responses", err)
+	}
+
+	defer conn.Close()
+
+	stop := watchContext(ctx, conn)
+	defer stop()
+
+	packet := newPingInfoPacket()
+	packet.Key = protocol.RandomKey()
+
+	data, err := packet.MarshalBinary()
+	if err != nil {
+		return output, fmt.Errorf("MarshalBinary failed: %w", err)
+	}
+
+	start := time.Now()
+	written := false
+
+	var writeErr error
+
+	for _, target := range targets {
+		address, err := net.ResolveUDPAddr("udp4", target)
+		if err != nil {
+			return output, fmt.Errorf("discover: [%s]: %w", target, newQueryError(err.Error(), kindOf(err), err))
+		}
+
+		// a network without a route is skipped rather than failing the whole discovery
+		_, err = conn.WriteTo(data, address)
+		if err != nil {
+			writeErr = err
+			continue
+		}
+
+		written = true
+	}
+
+	if !written {
+		if writeErr == nil {
+			return output, ErrorNoNetworks
+		}
+
+		return output, newQueryError(fmt.Sprintf("connection Write failed: %s", writeErr), kindOf(writeErr), writeErr)
+	}
+
+	// only the read deadline is set, any cancellation during the writes above still applies
+	err = conn.SetReadDeadline(deadline(ctx, options.Timeout))
+	if err != nil {
+		return output, err
+	}
+
+	if contextError(ctx) != nil {
+		return output, fmt.Errorf("discover: %w", contextError(ctx))
+	}
+
+	seen := make(map[string]bool)
+	buf := make([]byte, protocol.MaxPacketSize)
+
+	for {
+		length, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if contextError(ctx) != nil {
+				return output, fmt.Errorf("discover: %w", contextError(ctx))
+			}
+
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return output, nil
+			}
+
+			return output, connectionError(options, "connection read failed", err)
+		}
+
+		if _, ok := validResponse(buf[:length], packet.Key, protocol.PingInfoResponse); !ok || seen[addr.String()] {
+			continue
+		}
+
+		game := NewPingInfoQueryWithOptions(addr.String(), options)
+		game.requestStart = start
+		game.requestEnd = time.Now()
+
+		// a server that can't be decoded is left out, the rest of the network may still answer
+		if game.unmarshalResponse(buf[:length]) != nil {
+			continue
+		}
+
+		seen[addr.String()] = true
+		game.Ping = game.requestEnd.Sub(game.requestStart)
+
+		output = append(output, game)
+	}
+}
@@ -0,0 +1,146 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/stretchr/testify/suite"
+)
+
+type DiscoverTestSuite struct {
+	suite.Suite
+	Servers []*gametest.Server
+	Options *protocol.Options
+}
+
+func (t *DiscoverTestSuite) SetupTest() {
+	t.Servers = make([]*gametest.Server, 0)
+	t.Options = &protocol.Options{
+		Timeout: 200 * time.Millisecond,
+	}
+}
+
+func (t *DiscoverTestSuite) TearDownTest() {
+	for _, v := range t.Servers {
+		v.Close()
+	}
+}
+
+func (t *DiscoverTestSuite) newServer(name string, faults gametest.Faults) *gametest.Server {
+	info := gametest.DefaultPingInfo()
+	info.Name = []byte(name)
+
+	s := gametest.NewServer(info)
+	s.SetFaults(faults)
+
+	t.Servers = append(t.Servers, s)
+
+	return s
+}
+
+func (t *DiscoverTestSuite) targets() (output []string) {
+	for _, v := range t.Servers {
+		output = append(output, v.Address)
+	}
+
+	return
+}
+
+func (t *DiscoverTestSuite) TestBroadcastAddresses() {
+	host := func(ip string, ones int, bits int) *net.IPNet {
+		return &net.IPNet{IP: net.ParseIP(ip), Mask: net.CIDRMask(ones, bits)}
+	}
+
+	output := broadcastAddresses([]*net.IPNet{
+		host("192.168.1.20", 24, 32),
+		host("192.168.1.30", 24, 32),
+		host("10.1.2.3", 16, 32),
+		host("127.0.0.1", 8, 32),
+		host("10.8.0.1", 32, 32),
+		host("::1", 128, 128),
+		host("fe80::1", 64, 128),
+	})
+
+	t.Require().Len(output, 2)
+	t.Assert().Equal("192.168.1.255", output[0].String())
+	t.Assert().Equal("10.1.255.255", output[1].String())
+}
+
+func (t *DiscoverTestSuite) TestDiscover() {
+	t.newServer("alpha", gametest.Faults{})
+	t.newServer("bravo", gametest.Faults{Duplicate: true, Delay: 20 * time.Millisecond})
+	t.newServer("dropped", gametest.Faults{Drop: 1})
+	t.newServer("malformed", gametest.Faults{Malformed: true})
+
+	start := time.Now()
+
+	output, err := discover(context.Background(), t.Options, t.targets())
+	t.Require().Nil(err)
+
+	// every server is listened for until the timeout
+	t.Assert().GreaterOrEqual(int64(time.Since(start)), int64(t.Options.Timeout))
+
+	found := make([]string, 0)
+	for _, v := range output {
+		found = append(found, string(v.Name))
+		t.Assert().Greater(int64(v.Ping), int64(0))
+	}
+
+	sort.Strings(found)
+	t.Assert().Equal([]string{"alpha", "bravo"}, found)
+	t.Assert().Contains([]string{t.Servers[0].Address, t.Servers[1].Address}, output[0].Address)
+}
+
+func (t *DiscoverTestSuite) TestDiscover_Cancelled() {
+	t.Options.Timeout = 5 * time.Second
+
+	t.newServer("alpha", gametest.Faults{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+
+	output, err := discover(ctx, t.Options, t.targets())
+	t.Assert().True(errors.Is(err, context.Canceled))
+	t.Assert().Less(int64(time.Since(start)), int64(500*time.Millisecond))
+
+	// what answered before the cancellation is still returned
+	t.Require().Len(output, 1)
+	t.Assert().Equal("alpha", string(output[0].Name))
+}
+
+func (t *DiscoverTestSuite) TestDiscover_PortRange() {
+	for _, v := range [][2]int{{29002, 29001}, {0, 29001}, {29001, 65536}, {-1, -2}} {
+		output, err := Discover(context.Background(), t.Options, v[0], v[1])
+		t.Assert().True(errors.Is(err, ErrorPortRange), "%d-%d", v[0], v[1])
+		t.Assert().Empty(output)
+	}
+}
+
+func (t *DiscoverTestSuite) TestDiscover_WriteFailed() {
+	start := time.Now()
+
+	// nothing can be sent to port 0, so there's nothing to wait for
+	output, err := discover(context.Background(), t.Options, []string{"127.0.0.1:0"})
+	t.Assert().Contains(fmt.Sprint(err), "connection Write failed")
+
+	// the error is the one WriteTo returned, not ErrorNoNetworks
+	var opError *net.OpError
+	t.Require().True(errors.As(err, &opError), "%s", err)
+	t.Assert().Equal("write", opError.Op)
+	t.Assert().False(errors.Is(err, ErrorNoNetworks))
+	t.Assert().Empty(output)
+	t.Assert().Less(int64(time.Since(start)), int64(t.Options.Timeout))
+}
+
+func TestDiscoverTestSuite(t *testing.T) {
+	suite.Run(t, new(DiscoverTestSuite))
+}
@@ -0,0 +1,143 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// PartialResultError is returned when a multi packet response is missing fragments.
+// everything that did arrive is still merged into the query result
+type PartialResultError struct {
+	Total   int
+	Missing []int
+}
+
+func (e *PartialResultError) Error() string {
+	missing := make([]string, len(e.Missing))
+	for k, v := range e.Missing {
+		missing[k] = strconv.Itoa(v)
+	}
+
+	return fmt.Sprintf("partial result, missing packet(s) %s of %d", strings.Join(missing, ", "), e.Total)
+}
+
+// the cause of a failed query, matched with errors.Is
+var (
+	ErrorTimeout           = errors.New("connection timed out")
+	ErrorDNS               = errors.New("no such host")
+	ErrorRefused           = errors.New("connection refused")
+	ErrorMalformedResponse = errors.New("malformed response")
+)
+
+// ErrorNoNetworks is returned by Discover when there's no local ipv4 network to broadcast on
+var ErrorNoNetworks = errors.New("no local network to broadcast on")
+
+// ErrorPortRange is returned by Discover for ports outside 1-65535 or a first port after the last
+var ErrorPortRange = errors.New("invalid port range")
+
+// MalformedResponseError is returned when a response arrives but can't be decoded,
+// Err is the protocol error, usually a *protocol.DecodeError
+type MalformedResponseError struct {
+	Address string
+	Err     error
+
+	message string
+}
+
+// newMalformedResponseError uses debugMessage followed by the cause as the message when debugging
+func newMalformedResponseError(options *protocol.Options, address string, message string, debugMessage string, err error) error {
+	output := &MalformedResponseError{
+		Address: address,
+		Err:     err,
+		message: message,
+	}
+
+	if options.Debug {
+		output.message = fmt.Sprintf("%s: %s", debugMessage, err)
+	}
+
+	return output
+}
+
+func (e *MalformedResponseError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("malformed response from %s: %s", e.Address, e.Err)
+	}
+
+	return e.message
+}
+
+func (e *MalformedResponseError) Is(target error) bool {
+	return target == ErrorMalformedResponse
+}
+
+func (e *MalformedResponseError) Unwrap() error {
+	return e.Err
+}
+
+// queryError keeps an existing human readable message while matching kind with errors.Is
+type queryError struct {
+	message string
+	kind    error
+	cause   error
+}
+
+func newQueryError(message string, kind error, cause error) error {
+	return &queryError{
+		message: message,
+		kind:    kind,
+		cause:   cause,
+	}
+}
+
+func (e *queryError) Error() string {
+	return e.message
+}
+
+func (e *queryError) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
+func (e *queryError) Unwrap() error {
+	return e.cause
+}
+
+// kindOf classifies a network error as ErrorTimeout, ErrorDNS or ErrorRefused, or nil if it's none of them
+func kindOf(err error) error {
+	var dnsError *net.DNSError
+	if errors.As(err, &dnsError) {
+		return ErrorDNS
+	}
+
+	// icmp port unreachable is reported on connected udp sockets as ECONNREFUSED
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return ErrorRefused
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) && netError.Timeout() {
+		return ErrorTimeout
+	}
+
+	return nil
+}
+
+// connectionError classifies a failed read or write, the cause is only in the message when debugging
+func connectionError(options *protocol.Options, message string, err error) error {
+	kind := kindOf(err)
+	if kind == ErrorTimeout {
+		return ErrorTimeout
+	}
+
+	if options.Debug {
+		return newQueryError(fmt.Sprintf("%s: %s", message, err), kind, err)
+	}
+
+	return newQueryError(message, kind, err)
+}
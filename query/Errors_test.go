@@ -0,0 +1,133 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorsTestSuite struct {
+	suite.Suite
+	Options *protocol.Options
+}
+
+func (t *ErrorsTestSuite) SetupTest() {
+	t.Options = &protocol.Options{
+		Timeout: 100 * time.Millisecond,
+	}
+}
+
+// each test runs with and without Debug, causes are available either way
+func (t *ErrorsTestSuite) debug(test func()) {
+	for _, debug := range []bool{false, true} {
+		t.Options.Debug = debug
+		test()
+	}
+}
+
+func (t *ErrorsTestSuite) TestErrors_Timeout() {
+	s := gametest.NewServer(nil)
+	defer s.Close()
+
+	t.debug(func() {
+		s.SetFaults(gametest.Faults{Drop: 1})
+
+		err := NewPingInfoQueryWithOptions(s.Address, t.Options).Query()
+		t.Assert().True(errors.Is(err, ErrorTimeout))
+		t.Assert().EqualError(err, "pingInfo: ["+s.Address+"]: connection timed out")
+	})
+}
+
+func (t *ErrorsTestSuite) TestErrors_Malformed() {
+	s := gametest.NewServer(nil)
+	defer s.Close()
+
+	s.SetFaults(gametest.Faults{Malformed: true})
+
+	t.debug(func() {
+		err := NewPingInfoQueryWithOptions(s.Address, t.Options).Query()
+		t.Assert().True(errors.Is(err, ErrorMalformedResponse))
+		t.Assert().True(errors.Is(err, protocol.ErrorTruncatedPacket))
+
+		malformed := new(MalformedResponseError)
+		t.Require().True(errors.As(err, &malformed))
+		t.Assert().Equal(s.Address, malformed.Address)
+
+		decodeError := new(protocol.DecodeError)
+		t.Assert().True(errors.As(err, &decodeError))
+
+		if t.Options.Debug {
+			t.Assert().Contains(err.Error(), "unmarshaling pinginfo data failed: ")
+		} else {
+			t.Assert().EqualError(err, "pingInfo: ["+s.Address+"]: unspecified error parsing ping response")
+		}
+	})
+}
+
+func (t *ErrorsTestSuite) TestErrors_Refused() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+
+	address := conn.LocalAddr().String()
+	_ = conn.Close()
+
+	t.debug(func() {
+		err := NewPingInfoQueryWithOptions(address, t.Options).Query()
+		t.Assert().True(errors.Is(err, ErrorRefused))
+		t.Assert().Contains(err.Error(), "connection read failed")
+
+		err = NewMasterQueryWithOptions(address, t.Options).Query()
+		t.Assert().True(errors.Is(err, ErrorRefused))
+	})
+}
+
+func (t *ErrorsTestSuite) TestErrors_DNS() {
+	t.debug(func() {
+		err := NewPingInfoQueryWithOptions("darkstar.invalid:29001", t.Options).Query()
+		t.Assert().True(errors.Is(err, ErrorDNS))
+
+		t.Assert().True(strings.HasPrefix(err.Error(), "pingInfo: [darkstar.invalid:29001]: "))
+
+		var dnsError *net.DNSError
+		t.Assert().True(errors.As(err, &dnsError))
+
+		err = NewGameInfoQueryWithOptions("darkstar.invalid:29001", t.Options).Query()
+		t.Assert().True(errors.Is(err, ErrorDNS))
+		t.Assert().True(strings.HasPrefix(err.Error(), "gameInfo: [darkstar.invalid:29001]: "))
+
+		_, err = NewPingInfoQueryWithOptions("darkstar.invalid:29001", t.Options).Sample(context.Background(), 1, 0)
+		t.Assert().True(errors.Is(err, ErrorDNS))
+		t.Assert().True(strings.HasPrefix(err.Error(), "pingInfo: [darkstar.invalid:29001]: "))
+
+		err = NewMasterQueryWithOptions("darkstar.invalid:29001", t.Options).Query()
+		t.Assert().True(errors.Is(err, ErrorDNS))
+		t.Assert().True(errors.As(err, &dnsError))
+
+		if !t.Options.Debug {
+			t.Assert().EqualError(err, "master: [darkstar.invalid]: no such host")
+		}
+	})
+}
+
+func (t *ErrorsTestSuite) TestMalformedResponseError() {
+	err := &MalformedResponseError{
+		Address: "127.0.0.1:29001",
+		Err:     protocol.ErrorEmptyPacket,
+	}
+
+	t.Assert().EqualError(err, "malformed response from 127.0.0.1:29001: "+protocol.ErrorEmptyPacket.Error())
+	t.Assert().True(errors.Is(err, ErrorMalformedResponse))
+	t.Assert().True(errors.Is(err, protocol.ErrorEmptyPacket))
+	t.Assert().False(errors.Is(err, ErrorTimeout))
+}
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// GameInfoQuery sends a GameInfoQuery and decodes the header of the response with protocol.GameInfo,
+// the rest is left undecoded in Payload
+type GameInfoQuery struct {
+	singleQuery
+
+	*protocol.GameInfo
+}
+
+func NewGameInfoQuery(address string) *GameInfoQuery {
+	options := &protocol.Options{
+		Timeout: 2 * time.Second,
+		Debug:   false,
+	}
+
+	return NewGameInfoQueryWithOptions(address, options)
+}
+
+func NewGameInfoQueryWithOptions(address string, options *protocol.Options) *GameInfoQuery {
+	output := &GameInfoQuery{
+		singleQuery: newSingleQuery(options),
+		GameInfo:    protocol.NewGameInfo(),
+	}
+
+	output.Address = address
+
+	return output
+}
+
+func newGameInfoPacket() *protocol.Packet {
+	packet := protocol.NewPacket()
+	packet.Type = protocol.GameInfoQuery
+	packet.Number = protocol.RequestAllPackets
+
+	return packet
+}
+
+func (s *GameInfoQuery) unmarshalResponse(data []byte) error {
+	err := s.UnmarshalBinary(data)
+	if err != nil {
+		return newMalformedResponseError(s.options, s.Address, "unspecified error parsing game info response", "unmarshaling gameinfo data failed", err)
+	}
+
+	return nil
+}
+
+func (s *GameInfoQuery) Query() error {
+	return s.QueryContext(context.Background())
+}
+
+// QueryContext is Query, aborting the dial and read once ctx is done
+func (s *GameInfoQuery) QueryContext(ctx context.Context) error {
+	err := s.query(ctx, "gameInfo", s.Address, newGameInfoPacket, protocol.GameInfoResponse, s.unmarshalResponse)
+	if err != nil {
+		return err
+	}
+
+	s.Ping = s.requestEnd.Sub(s.requestStart)
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package query
+
+import (
+	"io"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// validResponse reports whether data is a well formed packet of the expected type answering key
+func validResponse(data []byte, key uint16, packetType protocol.PacketType) (packet *protocol.Packet, ok bool) {
+	packet, err := protocol.NewPacketWithData(data)
+	if err != nil {
+		return nil, false
+	}
+
+	return packet, packet.Key == key && packet.Type == packetType
+}
+
+// readResponse reads from conn until a packet of packetType answering key arrives, returning it and its raw data.
+// anything else is counted in discarded and we keep waiting until the deadline.
+// the socket is connected, so the os already drops datagrams from any other source
+func readResponse(conn io.Reader, key uint16, packetType protocol.PacketType, discarded *int) (data []byte, packet *protocol.Packet, err error) {
+	data = make([]byte, protocol.MaxPacketSize)
+
+	for {
+		length, err := conn.Read(data)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		packet, ok := validResponse(data[:length], key, packetType)
+		if !ok {
+			*discarded++
+			continue
+		}
+
+		return data[:length], packet, nil
+	}
+}
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -10,17 +11,21 @@ import (
 )
 
 const DefaultOptionsTimeoutDuration = 2 * time.Second
+const DefaultFragmentRetries = 3
 
 type MasterQuery struct {
 	txID uint16
 
 	*protocol.Master
 
-	Ping         time.Duration
+	Ping         time.Duration // round trip to the first packet of the list, re-requests for lost packets aren't counted
+	Partial      bool          // the list was still missing packets when the query returned a PartialResultError
 	conn         net.Conn
 	requestStart time.Time
 	requestEnd   time.Time
 	options      *protocol.Options
+	discarded    int
+	attempts     int
 }
 
 func newPacket() *protocol.Packet {
@@ -44,90 +49,241 @@ func NewMasterQueryWithOptions(address string, options *protocol.Options) (outpu
 	output = &MasterQuery{
 		Master:  protocol.NewMasterWithAddress(address),
 		options: options,
-		txID:    0,
+		txID:    protocol.RandomKey(),
 	}
 
 	return
 }
 
-func (m *MasterQuery) parseResponse() error {
+func (m *MasterQuery) parseResponse(ctx context.Context, key uint16) error {
 	// acquire data
-	m.Total = 1
-	for i := byte(0); i < m.Total; i++ {
-		data := make([]byte, protocol.MaxPacketSize)
+	fragments := make(map[byte][]byte)
+	total := byte(0)
+	retries := m.fragmentRetries()
 
-		length, err := m.conn.Read(data)
+	for total == 0 || len(fragments) < int(total) {
+		data, packet, err := readResponse(m.conn, key, protocol.MasterServerList, &m.discarded)
 		if err != nil {
+			if contextError(ctx) != nil {
+				return contextError(ctx)
+			}
+
 			var netError *net.OpError
 			if errors.As(err, &netError) && netError.Timeout() {
-				return fmt.Errorf("connection timed out")
-			}
+				if len(fragments) == 0 {
+					return ErrorTimeout
+				}
 
-			if m.options.Debug {
-				return fmt.Errorf("connection read failed: %w", err)
+				// ask again for only the fragments we're missing
+				if retries <= 0 {
+					break
+				}
+
+				err = m.requestFragments(ctx, key, missingFragments(fragments, total))
+				if err != nil {
+					if contextError(ctx) != nil {
+						return contextError(ctx)
+					}
+
+					break
+				}
+
+				retries--
+
+				continue
 			}
 
-			return fmt.Errorf("connection read failed")
+			return connectionError(m.options, "connection read failed", err)
 		}
 
-		m.Packet = protocol.NewPacket()
+		if total == 0 {
+			m.requestEnd = time.Now()
 
-		err = m.Packet.UnmarshalBinary(data)
-		if err != nil {
-			if m.options.Debug {
-				return fmt.Errorf("unmarshaling packet failed: %w", err)
+			total = packet.Total
+			if total == 0 {
+				total = 1
 			}
+		}
 
-			return fmt.Errorf("unspecified error parsing packet")
+		// single packet responses aren't numbered consistently between master implementations
+		number := packet.Number
+		if total == 1 {
+			number = 1
 		}
 
-		m.Master = protocol.NewMasterWithAddress(m.Address)
+		// out of range and duplicate fragments are ignored
+		if number < 1 || number > total {
+			continue
+		}
+
+		if _, exists := fragments[number]; exists {
+			continue
+		}
+
+		fragments[number] = data
+	}
+
+	return m.mergeFragments(fragments, total)
+}
+
+// mergeFragments unmarshals every received fragment in order into a single Master
+func (m *MasterQuery) mergeFragments(fragments map[byte][]byte, total byte) error {
+	m.Master = protocol.NewMasterWithAddress(m.Address)
+
+	for i := 1; i <= int(total); i++ {
+		data, exists := fragments[byte(i)]
+		if !exists {
+			continue
+		}
 
-		err = m.Master.UnmarshalBinary(data)
+		err := m.Master.UnmarshalBinary(data)
 		if err != nil {
-			if m.options.Debug {
-				return fmt.Errorf("unmarshaling master data failed: %w", err)
-			}
+			return newMalformedResponseError(m.options, m.Address, "unspecified error parsing master response", "unmarshaling master data failed", err)
+		}
+	}
 
-			return fmt.Errorf("unspecified error parsing master response")
+	missing := missingFragments(fragments, total)
+	if len(missing) > 0 {
+		return &PartialResultError{
+			Total:   int(total),
+			Missing: missing,
 		}
+	}
+
+	return nil
+}
+
+// requestFragments extends the deadline and re-requests individual packets of a master list by number
+func (m *MasterQuery) requestFragments(ctx context.Context, key uint16, numbers []int) error {
+	err := setDeadline(ctx, m.conn, attemptTimeout(m.options))
+	if err != nil {
+		return err
+	}
 
-		if length <= protocol.MaxPacketSize || m.Packet.Total <= 1 {
-			break
+	for _, v := range numbers {
+		query := newPacket()
+		query.Key = key
+		query.Number = byte(v)
+
+		data, err := query.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		_, err = m.conn.Write(data)
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+func (m *MasterQuery) fragmentRetries() int {
+	switch {
+	case m.options.FragmentRetries < 0:
+		return 0
+	case m.options.FragmentRetries == 0:
+		return DefaultFragmentRetries
+	default:
+		return m.options.FragmentRetries
+	}
+}
+
+// Discarded returns the number of packets ignored by the last query for not answering it
+func (m *MasterQuery) Discarded() int {
+	return m.discarded
+}
+
+func missingFragments(fragments map[byte][]byte, total byte) (output []int) {
+	output = make([]int, 0)
+
+	for i := 1; i <= int(total); i++ {
+		if _, exists := fragments[byte(i)]; !exists {
+			output = append(output, i)
+		}
+	}
+
+	return
+}
+
 func (m *MasterQuery) Query() (err error) {
-	m.conn, err = net.Dial("udp", m.Address)
+	return m.QueryContext(context.Background())
+}
+
+// QueryContext is Query, aborting the dial, reads and fragment retries once ctx is done
+func (m *MasterQuery) QueryContext(ctx context.Context) (err error) {
+	m.conn, err = (&net.Dialer{}).DialContext(ctx, "udp", m.Address)
 	if err != nil {
+		if contextError(ctx) != nil {
+			return fmt.Errorf("master: [%s]: %w", m.Address, contextError(ctx))
+		}
+
 		var dnsError *net.DNSError
 		if errors.As(err, &dnsError) {
 			if m.options.Debug {
-				return fmt.Errorf("master: [%s]: dns error during dial [%w]", dnsError.Name, err)
+				return fmt.Errorf("master: [%s]: %w", dnsError.Name, newQueryError(fmt.Sprintf("dns error during dial [%s]", err), ErrorDNS, err))
 			}
 
-			return fmt.Errorf("master: [%s]: no such host", dnsError.Name)
+			return fmt.Errorf("master: [%s]: %w", dnsError.Name, newQueryError("no such host", ErrorDNS, err))
 		}
 
 		if m.options.Debug {
-			return fmt.Errorf("master: [%s]: error during dial [%w]", dnsError.Name, err)
+			return fmt.Errorf("master: [%s]: %w", m.Address, newQueryError(fmt.Sprintf("error during dial [%s]", err), kindOf(err), err))
 		}
 
-		return fmt.Errorf("master: [%s]: unspecified error during network connection", m.Address)
+		return fmt.Errorf("master: [%s]: %w", m.Address, newQueryError("unspecified error during network connection", kindOf(err), err))
 	}
 
 	defer m.conn.Close()
 
-	err = m.conn.SetDeadline(time.Now().Add(m.options.Timeout))
+	stop := watchContext(ctx, m.conn)
+	defer stop()
+
+	err = setDeadline(ctx, m.conn, m.options.Timeout)
 	if err != nil {
+		if contextError(ctx) != nil {
+			return fmt.Errorf("master: [%s]: %w", m.Address, contextError(ctx))
+		}
+
 		if m.options.Debug {
-			return fmt.Errorf("master: [%s]: error during m.conn.SetDeadline [%w]", m.Address, err)
+			return fmt.Errorf("master: [%s]: %w", m.Address, newQueryError(fmt.Sprintf("error during m.conn.SetDeadline [%s]", err), nil, err))
 		}
 
-		return fmt.Errorf("master: [%s]: unspecified error while setting connection timeout", m.Address)
+		return fmt.Errorf("master: [%s]: %w", m.Address, newQueryError("unspecified error while setting connection timeout", nil, err))
+	}
+
+	m.discarded = 0
+	m.Partial = false
+
+	m.attempts, err = retry(ctx, m.options, func(timeout time.Duration) error {
+		return m.attempt(ctx, timeout)
+	})
+
+	if err != nil && contextError(ctx) != nil {
+		return fmt.Errorf("master: [%s]: %w", m.Address, contextError(ctx))
+	}
+
+	// a partial master list is still a usable response
+	var partial *PartialResultError
+	if err != nil && !errors.As(err, &partial) {
+		return fmt.Errorf("master: [%s]: %w", m.Address, err)
+	}
+
+	m.Ping = m.requestEnd.Sub(m.requestStart)
+
+	if err != nil {
+		m.Partial = true
+		return fmt.Errorf("master: [%s]: %w", m.Address, err)
+	}
+
+	return
+}
+
+func (m *MasterQuery) attempt(ctx context.Context, timeout time.Duration) error {
+	err := setDeadline(ctx, m.conn, timeout)
+	if err != nil {
+		return err
 	}
 
 	query := newPacket()
@@ -139,10 +295,10 @@ func (m *MasterQuery) Query() (err error) {
 	data, err := query.MarshalBinary()
 	if err != nil {
 		if m.options.Debug {
-			return fmt.Errorf("master: [%s]: MarshalBinary failed: %w", m.Address, err)
+			return newQueryError(fmt.Sprintf("MarshalBinary failed: %s", err), nil, err)
 		}
 
-		return fmt.Errorf("master: [%s]: Error parsing response", m.Address)
+		return newQueryError("Error parsing response", nil, err)
 	}
 
 	m.requestStart = time.Now()
@@ -150,21 +306,16 @@ func (m *MasterQuery) Query() (err error) {
 	_, err = m.conn.Write(data)
 	if err != nil {
 		if m.options.Debug {
-			return fmt.Errorf("master: [%s]: m.Conn.Write failed: %w", m.Address, err)
+			return newQueryError(fmt.Sprintf("m.Conn.Write failed: %s", err), kindOf(err), err)
 		}
 
-		return fmt.Errorf("master: [%s]: connection refused", m.Address)
-	}
-
-	_ = m.conn.SetDeadline(time.Now().Add(m.options.Timeout))
-
-	err = m.parseResponse()
-	if err != nil {
-		return fmt.Errorf("master: [%s]: %w", m.Address, err)
+		return newQueryError("connection refused", kindOf(err), err)
 	}
 
-	m.requestEnd = time.Now()
-	m.Ping = m.requestEnd.Sub(m.requestStart)
+	return m.parseResponse(ctx, query.Key)
+}
 
-	return
+// Attempts returns the number of times the last query was sent before it succeeded or gave up
+func (m *MasterQuery) Attempts() int {
+	return m.attempts
 }
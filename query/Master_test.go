@@ -0,0 +1,275 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/server"
+	"github.com/stretchr/testify/suite"
+)
+
+type MasterQueryTestSuite struct {
+	suite.Suite
+	Master  *protocol.Master
+	Options *protocol.Options
+	Conn    net.PacketConn
+}
+
+func (t *MasterQueryTestSuite) SetupTest() {
+	var err error
+
+	t.Conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+
+	t.Master = protocol.NewMaster()
+	t.Master.CommonName = "Test Master"
+	t.Master.MOTD = "Welcome to the test master"
+	t.Master.MasterID = 42
+	t.Master.Servers = make(map[string]*server.Server)
+
+	for i := 0; i < 200; i++ {
+		address := fmt.Sprintf("96.126.117.%d:%d", i%250+1, 29001+i/250)
+		t.Master.Servers[address], _ = server.NewServerFromString(address)
+	}
+
+	t.Options = &protocol.Options{
+		Timeout:             250 * time.Millisecond,
+		MaxServerPacketSize: 512,
+	}
+}
+
+func (t *MasterQueryTestSuite) TearDownTest() {
+	_ = t.Conn.Close()
+}
+
+// serve answers requests until the connection is closed, honouring per packet requests
+// respond lets the test reorder or drop the packets about to be sent
+func (t *MasterQueryTestSuite) serve(respond func(request *protocol.Packet, packets [][]byte) [][]byte) <-chan *protocol.Packet {
+	requests := make(chan *protocol.Packet, 64)
+
+	go func() {
+		defer close(requests)
+
+		buf := make([]byte, protocol.MaxPacketSize)
+
+		for {
+			n, addr, err := t.Conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			request, err := protocol.NewPacketWithData(buf[:n])
+			if err != nil {
+				continue
+			}
+
+			requests <- request
+
+			packets := t.Master.GeneratePackets(t.Options, request.Key, nil, addr)
+			for _, v := range respond(request, protocol.RequestedPackets(packets, request.Number)) {
+				_, _ = t.Conn.WriteTo(v, addr)
+			}
+		}
+	}()
+
+	return requests
+}
+
+// drop filters out the given packet numbers
+func drop(packets [][]byte, numbers ...byte) (output [][]byte) {
+	output = make([][]byte, 0)
+
+	for _, v := range packets {
+		p, _ := protocol.NewPacketWithData(v)
+
+		keep := true
+		for _, number := range numbers {
+			if p.Number == number {
+				keep = false
+			}
+		}
+
+		if keep {
+			output = append(output, v)
+		}
+	}
+
+	return
+}
+
+func (t *MasterQueryTestSuite) TestMasterQuery_Spanned_OutOfOrder() {
+	t.serve(func(request *protocol.Packet, packets [][]byte) [][]byte {
+		reversed := make([][]byte, 0, len(packets)+1)
+		for i := len(packets) - 1; i >= 0; i-- {
+			reversed = append(reversed, packets[i])
+		}
+
+		// duplicate fragments are ignored
+		return append(reversed, packets[0])
+	})
+
+	m := NewMasterQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	err := m.Query()
+	t.Assert().Nil(err)
+
+	t.Assert().Equal(t.Master.CommonName, m.CommonName)
+	t.Assert().Equal(t.Master.MOTD, m.MOTD)
+	t.Assert().Equal(t.Master.MasterID, m.MasterID)
+	t.Assert().Len(m.Servers, len(t.Master.Servers))
+	t.Assert().Greater(int64(m.Ping), int64(0))
+}
+
+func (t *MasterQueryTestSuite) TestMasterQuery_Spoofed() {
+	t.serve(func(request *protocol.Packet, packets [][]byte) [][]byte {
+		foreign := t.Master.GeneratePackets(t.Options, request.Key+1, nil, t.Conn.LocalAddr())
+
+		wrongType := make([]byte, len(packets[0]))
+		copy(wrongType, packets[0])
+		wrongType[1] = byte(protocol.PingInfoResponse)
+
+		spoofed := [][]byte{{0xde, 0xad, 0xbe, 0xef}, foreign[0], wrongType}
+
+		return append(spoofed, packets...)
+	})
+
+	m := NewMasterQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	err := m.Query()
+	t.Assert().Nil(err)
+	t.Assert().Len(m.Servers, len(t.Master.Servers))
+	t.Assert().Equal(3, m.Discarded())
+}
+
+func (t *MasterQueryTestSuite) TestMasterQuery_Spanned_Retransmit() {
+	requests := t.serve(func(request *protocol.Packet, packets [][]byte) [][]byte {
+		if request.Number == protocol.RequestAllPackets {
+			return drop(packets, 2, 3)
+		}
+
+		return packets
+	})
+
+	m := NewMasterQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	err := m.Query()
+	t.Assert().Nil(err)
+	t.Assert().Len(m.Servers, len(t.Master.Servers))
+
+	t.Assert().Equal(byte(protocol.RequestAllPackets), (<-requests).Number)
+	t.Assert().Equal(byte(2), (<-requests).Number)
+	t.Assert().Equal(byte(3), (<-requests).Number)
+	t.Assert().Len(requests, 0)
+
+	t.Assert().False(m.Partial)
+
+	// waiting on the lost packets isn't part of the round trip
+	t.Assert().Less(int64(m.Ping), int64(t.Options.Timeout))
+}
+
+func (t *MasterQueryTestSuite) TestMasterQuery_Spanned_Partial() {
+	t.Options.FragmentRetries = 2
+
+	requests := t.serve(func(request *protocol.Packet, packets [][]byte) [][]byte {
+		return drop(packets, 2)
+	})
+
+	m := NewMasterQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	err := m.Query()
+
+	partial := new(PartialResultError)
+	t.Require().True(errors.As(err, &partial))
+	t.Assert().Equal([]int{2}, partial.Missing)
+	t.Assert().Contains(err.Error(), "missing packet(s) 2 of 3")
+
+	t.Assert().Equal(t.Master.CommonName, m.CommonName)
+	t.Assert().NotEmpty(m.Servers)
+	t.Assert().Less(len(m.Servers), len(t.Master.Servers))
+	t.Assert().Greater(int64(m.Ping), int64(0))
+	t.Assert().True(m.Partial)
+
+	// the original request plus one retransmission request per retry
+	t.Assert().Len(requests, 1+t.Options.FragmentRetries)
+}
+
+func (t *MasterQueryTestSuite) TestMasterQuery_Spanned_NoRetries() {
+	t.Options.FragmentRetries = -1
+
+	requests := t.serve(func(request *protocol.Packet, packets [][]byte) [][]byte {
+		return drop(packets, 3)
+	})
+
+	m := NewMasterQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	err := m.Query()
+
+	partial := new(PartialResultError)
+	t.Require().True(errors.As(err, &partial))
+	t.Assert().Equal([]int{3}, partial.Missing)
+	t.Assert().Len(requests, 1)
+}
+
+func (t *MasterQueryTestSuite) TestMasterQuery_Retry() {
+	t.Options.Attempts = 2
+	t.Options.AttemptTimeout = 50 * time.Millisecond
+
+	count := 0
+	requests := t.serve(func(request *protocol.Packet, packets [][]byte) [][]byte {
+		count++
+		if count == 1 {
+			return nil
+		}
+
+		return packets
+	})
+
+	m := NewMasterQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	t.Require().Nil(m.Query())
+	t.Assert().Len(m.Servers, len(t.Master.Servers))
+	t.Assert().Equal(2, m.Attempts())
+
+	first, second := <-requests, <-requests
+	t.Assert().Equal(first.Key+1, second.Key)
+}
+
+func (t *MasterQueryTestSuite) TestMasterQuery_Context_Cancel() {
+	t.serve(func(request *protocol.Packet, packets [][]byte) [][]byte {
+		return nil
+	})
+
+	t.Options.Timeout = 5 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+
+	m := NewMasterQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	err := m.QueryContext(ctx)
+	t.Assert().True(errors.Is(err, context.Canceled))
+	t.Assert().Less(int64(time.Since(start)), int64(500*time.Millisecond))
+}
+
+func (t *MasterQueryTestSuite) TestMasterQuery_Context_Deadline() {
+	// only the first fragment ever arrives, so retries would otherwise run for Timeout * 4
+	t.serve(func(request *protocol.Packet, packets [][]byte) [][]byte {
+		return drop(packets, 2, 3)
+	})
+
+	t.Options.Timeout = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	m := NewMasterQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	err := m.QueryContext(ctx)
+	t.Assert().True(errors.Is(err, context.DeadlineExceeded))
+	t.Assert().Less(int64(time.Since(start)), int64(500*time.Millisecond))
+}
+
+func TestMasterQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(MasterQueryTestSuite))
+}
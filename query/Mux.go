@@ -0,0 +1,261 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+var ErrorMuxClosed = errors.New("mux closed")
+
+type muxKey struct {
+	address string
+	key     uint16
+}
+
+type muxPending struct {
+	packetType protocol.PacketType
+	reply      chan []byte
+}
+
+// Mux sends queries to many servers through a single bound net.PacketConn,
+// demultiplexing the replies by source address, Packet.Key and Packet.Type
+type Mux struct {
+	sync.Mutex
+
+	conn      net.PacketConn
+	pending   map[muxKey]*muxPending
+	txID      uint16
+	discarded int
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewMux starts reading replies from conn, the caller still owns conn and should Close it after the Mux
+func NewMux(conn net.PacketConn) *Mux {
+	m := &Mux{
+		conn:    conn,
+		pending: make(map[muxKey]*muxPending),
+		txID:    protocol.RandomKey(),
+		done:    make(chan struct{}),
+	}
+
+	m.wg.Add(1)
+
+	go m.read()
+
+	return m
+}
+
+// Close stops the Mux, failing any outstanding queries with ErrorMuxClosed
+func (m *Mux) Close() error {
+	if !m.shutdown() {
+		return nil
+	}
+
+	// unblock the reader without closing the callers socket
+	err := m.conn.SetReadDeadline(time.Unix(1, 0))
+	m.wg.Wait()
+
+	_ = m.conn.SetReadDeadline(time.Time{})
+
+	return err
+}
+
+// shutdown marks the Mux as closed, returning false if it already was
+func (m *Mux) shutdown() bool {
+	m.Lock()
+	defer m.Unlock()
+
+	select {
+	case <-m.done:
+		return false
+	default:
+		close(m.done)
+		return true
+	}
+}
+
+func (m *Mux) read() {
+	defer m.wg.Done()
+
+	buf := make([]byte, protocol.MaxPacketSize)
+
+	for {
+		n, addr, err := m.conn.ReadFrom(buf)
+		if err != nil {
+			// windows reports icmp port unreachable from an earlier write as a read error
+			var syscallError *os.SyscallError
+			if errors.As(err, &syscallError) {
+				continue
+			}
+
+			// the socket was closed or Close was called, either way nothing more can be read
+			m.shutdown()
+
+			return
+		}
+
+		packet, err := protocol.NewPacketWithData(buf[:n])
+		if err != nil {
+			m.discard()
+			continue
+		}
+
+		key := muxKey{
+			address: addr.String(),
+			key:     packet.Key,
+		}
+
+		// anything not answering an outstanding query is discarded, that query keeps waiting
+		m.Lock()
+		pending, exists := m.pending[key]
+		if exists && pending.packetType == packet.Type {
+			delete(m.pending, key)
+		} else {
+			exists = false
+			m.discarded++
+		}
+		m.Unlock()
+
+		if !exists {
+			continue
+		}
+
+		data := make([]byte, n)
+		copy(data, buf[:n])
+
+		pending.reply <- data
+	}
+}
+
+func (m *Mux) discard() {
+	m.Lock()
+	m.discarded++
+	m.Unlock()
+}
+
+// Discarded returns the number of packets received that didn't answer any outstanding query
+func (m *Mux) Discarded() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.discarded
+}
+
+// register reserves a key unused by any other outstanding query to address
+func (m *Mux) register(address string, packetType protocol.PacketType) (key muxKey, reply chan []byte, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	select {
+	case <-m.done:
+		return key, nil, ErrorMuxClosed
+	default:
+	}
+
+	key.address = address
+
+	for i := 0; i <= 0xffff; i++ {
+		key.key = m.txID
+		m.txID++
+
+		if _, exists := m.pending[key]; !exists {
+			reply = make(chan []byte, 1)
+			m.pending[key] = &muxPending{
+				packetType: packetType,
+				reply:      reply,
+			}
+
+			return key, reply, nil
+		}
+	}
+
+	return key, nil, fmt.Errorf("no free packet keys for %s", address)
+}
+
+func (m *Mux) unregister(key muxKey) {
+	m.Lock()
+	delete(m.pending, key)
+	m.Unlock()
+}
+
+// exchange sends packet to address with a newly assigned Key and waits for the first reply of packetType to it
+func (m *Mux) exchange(ctx context.Context, address *net.UDPAddr, packet *protocol.Packet, packetType protocol.PacketType, timeout time.Duration) ([]byte, error) {
+	key, reply, err := m.register(address.String(), packetType)
+	if err != nil {
+		return nil, err
+	}
+
+	defer m.unregister(key)
+
+	packet.Key = key.key
+
+	data, err := packet.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = m.conn.WriteTo(data, address)
+	if err != nil {
+		return nil, newQueryError(fmt.Sprintf("connection Write failed: %s", err), kindOf(err), err)
+	}
+
+	timer := time.NewTimer(time.Until(deadline(ctx, timeout)))
+	defer timer.Stop()
+
+	select {
+	case data = <-reply:
+		return data, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-timer.C:
+		err = contextError(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, ErrorTimeout
+
+	case <-m.done:
+		return nil, ErrorMuxClosed
+	}
+}
+
+// resolve is net.ResolveUDPAddr, giving up once ctx is done.
+// an ipv4 address is preferred when the host has both, as net.ResolveUDPAddr does
+func resolve(ctx context.Context, address string) (*net.UDPAddr, error) {
+	host, service, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := net.DefaultResolver.LookupPort(ctx, "udp", service)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &net.UDPAddr{IP: addrs[0].IP, Port: port, Zone: addrs[0].Zone}
+
+	for _, v := range addrs {
+		if v.IP.To4() != nil {
+			output = &net.UDPAddr{IP: v.IP, Port: port}
+			break
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,146 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/stretchr/testify/suite"
+)
+
+type MuxTestSuite struct {
+	suite.Suite
+	Conn    net.PacketConn
+	Mux     *Mux
+	Options *protocol.Options
+}
+
+func (t *MuxTestSuite) SetupTest() {
+	var err error
+
+	t.Conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+
+	t.Mux = NewMux(t.Conn)
+	t.Options = &protocol.Options{
+		Timeout: 200 * time.Millisecond,
+	}
+}
+
+func (t *MuxTestSuite) TearDownTest() {
+	_ = t.Mux.Close()
+	_ = t.Conn.Close()
+}
+
+func (t *MuxTestSuite) TestMux_QueryMux() {
+	servers := make([]*gametest.Server, 0)
+
+	for _, name := range []string{"alpha", "bravo", "charlie"} {
+		info := gametest.DefaultPingInfo()
+		info.Name = []byte(name)
+
+		s := gametest.NewServer(info)
+		defer s.Close()
+
+		servers = append(servers, s)
+	}
+
+	servers[1].SetFaults(gametest.Faults{Duplicate: true, Delay: 20 * time.Millisecond})
+
+	for _, s := range servers {
+		q := NewPingInfoQueryWithOptions(s.Address, t.Options)
+
+		err := q.QueryMux(context.Background(), t.Mux)
+		t.Require().Nil(err)
+		t.Assert().Equal(s.PingInfo.Name, q.Name)
+		t.Assert().Greater(int64(q.Ping), int64(0))
+	}
+
+	// every query went out through the one socket
+	for _, s := range servers {
+		t.Assert().Len(s.Requests(), 1)
+	}
+}
+
+func (t *MuxTestSuite) TestMux_IgnoresForeignReplies() {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+
+	defer server.Close()
+
+	go func() {
+		buf := make([]byte, protocol.MaxPacketSize)
+
+		n, addr, err := server.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		request, _ := protocol.NewPacketWithData(buf[:n])
+
+		info := gametest.DefaultPingInfo()
+		info.Packet = protocol.NewPacket()
+		info.Key = request.Key + 1
+
+		data, _ := info.MarshalBinary()
+		_, _ = server.WriteTo(data, addr)
+
+		// the right key as the wrong type
+		info.Key = request.Key
+
+		data, _ = info.MarshalBinary()
+		data[1] = byte(protocol.GameInfoResponse)
+		_, _ = server.WriteTo(data, addr)
+
+		_, _ = server.WriteTo([]byte{0xff}, addr)
+	}()
+
+	q := NewPingInfoQueryWithOptions(server.LocalAddr().String(), t.Options)
+	t.Assert().Contains(q.QueryMux(context.Background(), t.Mux).Error(), "connection timed out")
+	t.Assert().Equal(3, t.Mux.Discarded())
+}
+
+func (t *MuxTestSuite) TestMux_Close() {
+	s := gametest.NewServer(nil)
+	defer s.Close()
+
+	s.SetFaults(gametest.Faults{Drop: 1})
+
+	t.Options.Timeout = 5 * time.Second
+	time.AfterFunc(50*time.Millisecond, func() {
+		_ = t.Mux.Close()
+	})
+
+	q := NewPingInfoQueryWithOptions(s.Address, t.Options)
+	t.Assert().True(errors.Is(q.QueryMux(context.Background(), t.Mux), ErrorMuxClosed))
+
+	// the callers socket is left open
+	_, err := t.Conn.WriteTo([]byte{0}, t.Conn.LocalAddr())
+	t.Assert().Nil(err)
+}
+
+func (t *MuxTestSuite) TestMux_Resolve() {
+	address, err := resolve(context.Background(), "127.0.0.1:29001")
+	t.Require().Nil(err)
+	t.Assert().Equal("127.0.0.1:29001", address.String())
+
+	_, err = resolve(context.Background(), "darkstar.invalid:29001")
+	t.Assert().True(errors.Is(kindOf(err), ErrorDNS))
+}
+
+func (t *MuxTestSuite) TestMux_Resolve_Cancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the lookup is abandoned rather than waiting on dns
+	q := NewPingInfoQueryWithOptions("darkstar.invalid:29001", t.Options)
+	t.Assert().True(errors.Is(q.QueryMux(ctx, t.Mux), context.Canceled))
+}
+
+func TestMuxTestSuite(t *testing.T) {
+	suite.Run(t, new(MuxTestSuite))
+}
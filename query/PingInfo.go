@@ -1,24 +1,17 @@
 package query
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"io"
-	"net"
 	"time"
 
 	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
 )
 
 type PingInfoQuery struct {
-	txID uint16
+	singleQuery
 
 	*protocol.PingInfo
-
-	conn         net.Conn
-	requestStart time.Time
-	requestEnd   time.Time
-	options      *protocol.Options
 }
 
 func NewPingInfoQuery(address string) *PingInfoQuery {
@@ -32,8 +25,7 @@ func NewPingInfoQuery(address string) *PingInfoQuery {
 
 func NewPingInfoQueryWithOptions(address string, options *protocol.Options) *PingInfoQuery {
 	output := &PingInfoQuery{
-		options: options,
-		txID:    0,
+		singleQuery: newSingleQuery(options),
 		PingInfo: &protocol.PingInfo{
 			Address: address,
 		},
@@ -50,70 +42,62 @@ func newPingInfoPacket() *protocol.Packet {
 	return packet
 }
 
-func (s *PingInfoQuery) parseResponse(conn io.Reader) error {
-	data := make([]byte, protocol.MaxPacketSize)
-
-	_, err := conn.Read(data)
-	if err != nil {
-		var netError *net.OpError
-		if errors.As(err, &netError) && netError.Timeout() {
-			return fmt.Errorf("connection timed out")
-		}
-
-		if s.options.Debug {
-			return fmt.Errorf("connection read failed: %w", err)
-		}
-
-		return fmt.Errorf("connection read failed")
-	}
-
-	err = s.UnmarshalBinary(data)
+func (s *PingInfoQuery) unmarshalResponse(data []byte) error {
+	err := s.UnmarshalBinary(data)
 	if err != nil {
-		if s.options.Debug {
-			return fmt.Errorf("unmarshaling pinginfo data failed: %w", err)
-		}
-
-		return fmt.Errorf("unspecified error parsing ping response")
+		return newMalformedResponseError(s.options, s.Address, "unspecified error parsing ping response", "unmarshaling pinginfo data failed", err)
 	}
 
 	return nil
 }
 
 func (s *PingInfoQuery) Query() error {
-	var err error
+	return s.QueryContext(context.Background())
+}
 
-	s.conn, err = net.Dial("udp", s.Address)
+// QueryContext is Query, aborting the dial and read once ctx is done
+func (s *PingInfoQuery) QueryContext(ctx context.Context) error {
+	err := s.query(ctx, "pingInfo", s.Address, newPingInfoPacket, protocol.PingInfoResponse, s.unmarshalResponse)
 	if err != nil {
 		return err
 	}
 
-	defer s.conn.Close()
-
-	err = s.conn.SetDeadline(time.Now().Add(s.options.Timeout))
-	if err != nil {
-		return err
-	}
+	s.Ping = s.requestEnd.Sub(s.requestStart)
 
-	s.PingInfo.Packet = newPingInfoPacket()
-	s.PingInfo.Packet.Key = s.txID
+	return nil
+}
 
-	data, err := s.PingInfo.MarshalBinary()
+// QueryMux is QueryContext, sending the query through mux instead of dialing a new socket
+func (s *PingInfoQuery) QueryMux(ctx context.Context, mux *Mux) error {
+	address, err := resolve(ctx, s.Address)
 	if err != nil {
-		return fmt.Errorf("pingInfo: [%s]: MarshalBinary failed: %w", s.conn.RemoteAddr(), err)
+		if contextError(ctx) != nil {
+			return fmt.Errorf("pingInfo: [%s]: %w", s.Address, contextError(ctx))
+		}
+
+		return fmt.Errorf("pingInfo: [%s]: %w", s.Address, newQueryError(err.Error(), kindOf(err), err))
 	}
 
-	s.requestStart = time.Now()
+	s.discarded = 0
 
-	_, err = s.conn.Write(data)
-	if err != nil {
-		return fmt.Errorf("pingInfo: [%s]: connection Write failed: %w", s.conn.RemoteAddr(), err)
-	}
+	s.attempts, err = retry(ctx, s.options, func(timeout time.Duration) error {
+		s.PingInfo.Packet = newPingInfoPacket()
+		s.requestStart = time.Now()
+
+		data, err := mux.exchange(ctx, address, s.PingInfo.Packet, protocol.PingInfoResponse, timeout)
+		if err != nil {
+			return err
+		}
 
-	_ = s.conn.SetDeadline(time.Now().Add(s.options.Timeout))
+		return s.unmarshalResponse(data)
+	})
 
-	err = s.parseResponse(s.conn)
 	if err != nil {
-		return fmt.Errorf("pingInfo: [%s]: %w", s.conn.RemoteAddr(), err)
+		if contextError(ctx) != nil {
+			return fmt.Errorf("pingInfo: [%s]: %w", address, contextError(ctx))
+		}
+
+		return fmt.Errorf("pingInfo: [%s]: %w", address, err)
 	}
 
 	s.requestEnd = time.Now()
@@ -0,0 +1,155 @@
+package query
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/stretchr/testify/suite"
+)
+
+type PingInfoQueryTestSuite struct {
+	suite.Suite
+	Conn    net.PacketConn
+	Options *protocol.Options
+}
+
+func (t *PingInfoQueryTestSuite) SetupTest() {
+	var err error
+
+	t.Conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+	t.Require().Nil(err)
+
+	t.Options = &protocol.Options{
+		Timeout: 200 * time.Millisecond,
+	}
+}
+
+func (t *PingInfoQueryTestSuite) TearDownTest() {
+	_ = t.Conn.Close()
+}
+
+// serve answers every query with the packets returned by respond
+func (t *PingInfoQueryTestSuite) serve(respond func(request *protocol.Packet) [][]byte) <-chan *protocol.Packet {
+	requests := make(chan *protocol.Packet, 64)
+
+	go func() {
+		defer close(requests)
+
+		buf := make([]byte, protocol.MaxPacketSize)
+
+		for {
+			n, addr, err := t.Conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			request, err := protocol.NewPacketWithData(buf[:n])
+			if err != nil {
+				continue
+			}
+
+			requests <- request
+
+			for _, v := range respond(request) {
+				_, _ = t.Conn.WriteTo(v, addr)
+			}
+		}
+	}()
+
+	return requests
+}
+
+func pingInfoResponse(key uint16, packetType protocol.PacketType) []byte {
+	info := gametest.DefaultPingInfo()
+	info.Packet = protocol.NewPacket()
+	info.Key = key
+
+	data, _ := info.MarshalBinary()
+	data[1] = byte(packetType)
+
+	return data
+}
+
+func (t *PingInfoQueryTestSuite) TestPingInfoQuery_Spoofed() {
+	requests := t.serve(func(request *protocol.Packet) [][]byte {
+		return [][]byte{
+			{0x10},
+			pingInfoResponse(request.Key-1, protocol.PingInfoResponse),
+			pingInfoResponse(request.Key, protocol.MasterServerList),
+			pingInfoResponse(request.Key, protocol.PingInfoResponse),
+		}
+	})
+
+	q := NewPingInfoQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	err := q.Query()
+	t.Require().Nil(err)
+	t.Assert().Equal(gametest.DefaultPingInfo().Name, q.Name)
+	t.Assert().Equal(3, q.Discarded())
+
+	// each query uses the next key
+	first := <-requests
+
+	t.Require().Nil(q.Query())
+	t.Assert().Equal(first.Key+1, (<-requests).Key)
+}
+
+func (t *PingInfoQueryTestSuite) TestPingInfoQuery_SpoofedOnly() {
+	t.serve(func(request *protocol.Packet) [][]byte {
+		return [][]byte{
+			pingInfoResponse(request.Key+1, protocol.PingInfoResponse),
+		}
+	})
+
+	q := NewPingInfoQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	t.Assert().Contains(q.Query().Error(), "connection timed out")
+	t.Assert().Equal(1, q.Discarded())
+}
+
+func (t *PingInfoQueryTestSuite) TestPingInfoQuery_Retry() {
+	t.Options.Attempts = 3
+	t.Options.Backoff = 10 * time.Millisecond
+
+	// the first query goes unanswered until the second arrives, by which time its reply is discarded
+	count := 0
+	requests := t.serve(func(request *protocol.Packet) [][]byte {
+		count++
+		if count == 1 {
+			return nil
+		}
+
+		return [][]byte{
+			pingInfoResponse(request.Key-1, protocol.PingInfoResponse),
+			pingInfoResponse(request.Key, protocol.PingInfoResponse),
+		}
+	})
+
+	q := NewPingInfoQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	t.Require().Nil(q.Query())
+	t.Assert().Equal(2, q.Attempts())
+	t.Assert().Equal(1, q.Discarded())
+	t.Assert().Len(requests, 2)
+}
+
+func (t *PingInfoQueryTestSuite) TestPingInfoQuery_RetryExhausted() {
+	t.Options.Attempts = 2
+	t.Options.AttemptTimeout = 50 * time.Millisecond
+
+	requests := t.serve(func(request *protocol.Packet) [][]byte {
+		return nil
+	})
+
+	start := time.Now()
+
+	q := NewPingInfoQueryWithOptions(t.Conn.LocalAddr().String(), t.Options)
+	t.Assert().Contains(q.Query().Error(), "connection timed out")
+	t.Assert().Equal(2, q.Attempts())
+	t.Assert().Less(int64(time.Since(start)), int64(t.Options.Timeout))
+	t.Assert().Len(requests, 2)
+}
+
+func TestPingInfoQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(PingInfoQueryTestSuite))
+}
@@ -0,0 +1,244 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// PingStats summarises the round trips of several PingInfoQuery packets sent to a single server
+type PingStats struct {
+	Sent     int
+	Received int
+	Loss     float64 // percentage of queries that went unanswered
+
+	Min    time.Duration
+	Avg    time.Duration
+	Max    time.Duration
+	Median time.Duration
+	Jitter time.Duration // mean difference between consecutive round trips
+
+	Samples []time.Duration // round trips in the order the queries were sent, without the unanswered ones
+}
+
+func newPingStats(rtt []time.Duration, answered []bool) (output *PingStats) {
+	output = &PingStats{
+		Sent:    len(rtt),
+		Samples: make([]time.Duration, 0, len(rtt)),
+	}
+
+	for k, v := range rtt {
+		if answered[k] {
+			output.Samples = append(output.Samples, v)
+		}
+	}
+
+	output.Received = len(output.Samples)
+
+	if output.Sent > 0 {
+		output.Loss = float64(output.Sent-output.Received) / float64(output.Sent) * 100
+	}
+
+	if output.Received == 0 {
+		return
+	}
+
+	sorted := make([]time.Duration, len(output.Samples))
+	copy(sorted, output.Samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	output.Min = sorted[0]
+	output.Max = sorted[len(sorted)-1]
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		output.Median = (sorted[middle-1] + sorted[middle]) / 2
+	} else {
+		output.Median = sorted[middle]
+	}
+
+	total := time.Duration(0)
+	jitter := time.Duration(0)
+
+	for k, v := range output.Samples {
+		total += v
+
+		if k > 0 {
+			difference := v - output.Samples[k-1]
+			if difference < 0 {
+				difference = -difference
+			}
+
+			jitter += difference
+		}
+	}
+
+	output.Avg = total / time.Duration(output.Received)
+
+	if output.Received > 1 {
+		output.Jitter = jitter / time.Duration(output.Received-1)
+	}
+
+	return
+}
+
+// Sample sends count PingInfoQuery packets interval apart, waiting up to Timeout after the last one for replies.
+// the server's PingInfo is taken from the replies and Ping is set to the median round trip.
+// malformed replies and failed reads are counted as lost rather than ending the sample.
+// if nothing is answered the stats are still returned along with the last such error, or one matching ErrorTimeout
+func (s *PingInfoQuery) Sample(ctx context.Context, count int, interval time.Duration) (stats *PingStats, err error) {
+	if count < 1 {
+		count = 1
+	}
+
+	s.conn, err = (&net.Dialer{}).DialContext(ctx, "udp", s.Address)
+	if err != nil {
+		if contextError(ctx) != nil {
+			return nil, fmt.Errorf("pingInfo: [%s]: %w", s.Address, contextError(ctx))
+		}
+
+		return nil, fmt.Errorf("pingInfo: [%s]: %w", s.Address, newQueryError(err.Error(), kindOf(err), err))
+	}
+
+	defer s.conn.Close()
+
+	stop := watchContext(ctx, s.conn)
+	defer stop()
+
+	s.discarded = 0
+
+	rtt := make([]time.Duration, count)
+	answered := make([]bool, count)
+	sendTimes := make([]time.Time, count)
+	settled := make([]bool, count) // answered, or answered with something we couldn't decode
+	sent := make(map[uint16]int)
+
+	data := make([]byte, protocol.MaxPacketSize)
+	next := time.Now()
+	last := time.Time{}
+	received := 0
+	remaining := count
+
+	// the last malformed reply or failed read, returned if nothing is answered
+	var lost error
+
+	// the stats of whatever was sent are returned along with any error
+	result := func(err error) (*PingStats, error) {
+		return newPingStats(rtt[:len(sent)], answered[:len(sent)]), err
+	}
+
+	for remaining > 0 {
+		if len(sent) < count && !time.Now().Before(next) {
+			err = s.sendSample(sent, sendTimes)
+			if err != nil {
+				return result(fmt.Errorf("pingInfo: [%s]: %w", s.conn.RemoteAddr(), err))
+			}
+
+			next = next.Add(interval)
+
+			if len(sent) == count {
+				last = time.Now()
+			}
+		}
+
+		// wait for replies until the next query is due, or the timeout after the final one
+		wait := time.Until(next)
+		if len(sent) == count {
+			wait = time.Until(last.Add(s.options.Timeout))
+			if wait <= 0 {
+				break
+			}
+		}
+
+		// only the read deadline is set, a deadline already passed would otherwise fail the next write
+		err = s.conn.SetReadDeadline(deadline(ctx, wait))
+		if err == nil {
+			err = contextError(ctx)
+		}
+
+		if err != nil {
+			return result(fmt.Errorf("pingInfo: [%s]: %w", s.conn.RemoteAddr(), err))
+		}
+
+		length, err := s.conn.Read(data)
+		if err != nil {
+			if contextError(ctx) != nil {
+				return result(fmt.Errorf("pingInfo: [%s]: %w", s.conn.RemoteAddr(), contextError(ctx)))
+			}
+
+			// an icmp error such as a refused port can't be matched to a query, whichever it was is left unanswered
+			if kindOf(err) != ErrorTimeout {
+				lost = connectionError(s.options, "connection read failed", err)
+			}
+
+			continue
+		}
+
+		packet, err := protocol.NewPacketWithData(data[:length])
+		if err != nil || packet.Type != protocol.PingInfoResponse {
+			s.discarded++
+			continue
+		}
+
+		index, exists := sent[packet.Key]
+		if !exists || settled[index] {
+			s.discarded++
+			continue
+		}
+
+		settled[index] = true
+		remaining--
+
+		err = s.unmarshalResponse(data[:length])
+		if err != nil {
+			lost = err
+			continue
+		}
+
+		rtt[index] = time.Since(sendTimes[index])
+		answered[index] = true
+		received++
+	}
+
+	if received == 0 {
+		if lost == nil {
+			lost = ErrorTimeout
+		}
+
+		return result(fmt.Errorf("pingInfo: [%s]: %w", s.conn.RemoteAddr(), lost))
+	}
+
+	stats, err = result(nil)
+	s.Ping = stats.Median
+
+	return
+}
+
+// sendSample sends the next query of a Sample, recording its key and when it was sent
+func (s *PingInfoQuery) sendSample(sent map[uint16]int, sendTimes []time.Time) error {
+	s.PingInfo.Packet = newPingInfoPacket()
+	s.PingInfo.Packet.Key = s.txID
+	s.txID++
+
+	data, err := s.PingInfo.Packet.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("MarshalBinary failed: %w", err)
+	}
+
+	index := len(sent)
+	sendTimes[index] = time.Now()
+	sent[s.PingInfo.Packet.Key] = index
+
+	_, err = s.conn.Write(data)
+	if err != nil {
+		return newQueryError(fmt.Sprintf("connection Write failed: %s", err), kindOf(err), err)
+	}
+
+	return nil
+}
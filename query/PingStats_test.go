@@ -0,0 +1,186 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/gametest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/internal/udptest"
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/stretchr/testify/suite"
+)
+
+type PingStatsTestSuite struct {
+	suite.Suite
+	Server  *gametest.Server
+	Options *protocol.Options
+}
+
+func (t *PingStatsTestSuite) SetupTest() {
+	t.Server = gametest.NewServer(nil)
+	t.Options = &protocol.Options{
+		Timeout: 100 * time.Millisecond,
+	}
+}
+
+func (t *PingStatsTestSuite) TearDownTest() {
+	t.Server.Close()
+}
+
+func (t *PingStatsTestSuite) TestNewPingStats() {
+	ms := time.Millisecond
+
+	stats := newPingStats(
+		[]time.Duration{10 * ms, 0, 30 * ms, 20 * ms, 0},
+		[]bool{true, false, true, true, false},
+	)
+
+	t.Assert().Equal(5, stats.Sent)
+	t.Assert().Equal(3, stats.Received)
+	t.Assert().Equal(40.0, stats.Loss)
+	t.Assert().Equal([]time.Duration{10 * ms, 30 * ms, 20 * ms}, stats.Samples)
+	t.Assert().Equal(10*ms, stats.Min)
+	t.Assert().Equal(20*ms, stats.Avg)
+	t.Assert().Equal(30*ms, stats.Max)
+	t.Assert().Equal(20*ms, stats.Median)
+
+	// |30 - 10| and |20 - 30|
+	t.Assert().Equal(15*ms, stats.Jitter)
+
+	even := newPingStats([]time.Duration{10 * ms, 20 * ms}, []bool{true, true})
+	t.Assert().Equal(15*ms, even.Median)
+	t.Assert().Equal(10*ms, even.Jitter)
+
+	none := newPingStats([]time.Duration{0, 0}, []bool{false, false})
+	t.Assert().Equal(100.0, none.Loss)
+	t.Assert().Equal(time.Duration(0), none.Median)
+}
+
+func (t *PingStatsTestSuite) TestSample() {
+	t.Server.SetFaults(gametest.Faults{Duplicate: true})
+
+	q := NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+
+	start := time.Now()
+
+	stats, err := q.Sample(context.Background(), 5, 10*time.Millisecond)
+	t.Require().Nil(err)
+
+	t.Assert().GreaterOrEqual(int64(time.Since(start)), int64(40*time.Millisecond))
+	t.Assert().Equal(5, stats.Sent)
+	t.Assert().Equal(5, stats.Received)
+	t.Assert().Equal(0.0, stats.Loss)
+	t.Assert().Len(stats.Samples, 5)
+	t.Assert().LessOrEqual(int64(stats.Min), int64(stats.Median))
+	t.Assert().LessOrEqual(int64(stats.Median), int64(stats.Max))
+	t.Assert().Equal(stats.Median, q.Ping)
+	t.Assert().Equal(t.Server.PingInfo.Name, q.Name)
+	// the duplicate of the final reply may arrive after Sample has returned
+	t.Assert().GreaterOrEqual(q.Discarded(), 4)
+	t.Assert().Len(t.Server.Requests(), 5)
+}
+
+func (t *PingStatsTestSuite) TestSample_Loss() {
+	t.Server.SetFaults(gametest.Faults{Drop: 2})
+
+	q := NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+
+	stats, err := q.Sample(context.Background(), 5, 0)
+	t.Require().Nil(err)
+	t.Assert().Equal(5, stats.Sent)
+	t.Assert().Equal(3, stats.Received)
+	t.Assert().Equal(40.0, stats.Loss)
+}
+
+func (t *PingStatsTestSuite) TestSample_Timeout() {
+	t.Server.SetFaults(gametest.Faults{Drop: 3})
+
+	q := NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+
+	stats, err := q.Sample(context.Background(), 3, 0)
+	t.Assert().True(errors.Is(err, ErrorTimeout))
+	t.Require().NotNil(stats)
+	t.Assert().Equal(3, stats.Sent)
+	t.Assert().Equal(0, stats.Received)
+	t.Assert().Equal(100.0, stats.Loss)
+}
+
+func (t *PingStatsTestSuite) TestSample_Malformed() {
+	requests := 0
+
+	// the third query is answered with a reply too short to decode
+	server := udptest.NewServer("malformed", func(request *protocol.Packet, _ net.Addr) *udptest.Response {
+		requests++
+
+		response := *gametest.DefaultPingInfo()
+		response.Packet = protocol.NewPacket()
+		response.Key = request.Key
+		response.Number = protocol.RequestAllPackets
+
+		data, err := response.MarshalBinary()
+		if err != nil {
+			return nil
+		}
+
+		if requests == 3 {
+			data = data[:protocol.HeaderSize+3]
+		}
+
+		return &udptest.Response{Packets: [][]byte{data}}
+	})
+	server.Start()
+
+	defer server.Close()
+
+	q := NewPingInfoQueryWithOptions(server.Address, t.Options)
+
+	stats, err := q.Sample(context.Background(), 5, 10*time.Millisecond)
+	t.Require().Nil(err)
+	t.Assert().Equal(5, stats.Sent)
+	t.Assert().Equal(4, stats.Received)
+	t.Assert().Equal(20.0, stats.Loss)
+	t.Assert().Len(server.Requests(), 5)
+}
+
+func (t *PingStatsTestSuite) TestSample_AllMalformed() {
+	t.Server.SetFaults(gametest.Faults{Malformed: true})
+
+	q := NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+
+	stats, err := q.Sample(context.Background(), 3, 0)
+	t.Assert().True(errors.Is(err, ErrorMalformedResponse))
+	t.Require().NotNil(stats)
+	t.Assert().Equal(3, stats.Sent)
+	t.Assert().Equal(0, stats.Received)
+}
+
+func (t *PingStatsTestSuite) TestSample_Refused() {
+	// nothing listens on a closed port, so every query is refused but all of them are still sent
+	t.Server.Close()
+
+	q := NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+
+	stats, err := q.Sample(context.Background(), 3, 10*time.Millisecond)
+	t.Assert().True(errors.Is(err, ErrorRefused))
+	t.Require().NotNil(stats)
+	t.Assert().Equal(3, stats.Sent)
+	t.Assert().Equal(0, stats.Received)
+}
+
+func (t *PingStatsTestSuite) TestSample_Cancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(30*time.Millisecond, cancel)
+
+	q := NewPingInfoQueryWithOptions(t.Server.Address, t.Options)
+
+	stats, err := q.Sample(ctx, 100, 10*time.Millisecond)
+	t.Assert().True(errors.Is(err, context.Canceled))
+	t.Assert().Less(stats.Sent, 100)
+}
+
+func TestPingStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(PingStatsTestSuite))
+}
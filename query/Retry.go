@@ -0,0 +1,186 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"net"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+)
+
+// singleQuery is the part of PingInfoQuery and GameInfoQuery that sends a query answered by a single packet
+type singleQuery struct {
+	txID uint16
+
+	conn         net.Conn
+	requestStart time.Time
+	requestEnd   time.Time
+	options      *protocol.Options
+	discarded    int
+	attempts     int
+}
+
+func newSingleQuery(options *protocol.Options) singleQuery {
+	return singleQuery{
+		options: options,
+		txID:    protocol.RandomKey(),
+	}
+}
+
+// query dials address and sends a packet from newPacket until unmarshal accepts a responseType reply to it,
+// aborting the dial and read once ctx is done. errors are prefixed with name and the address
+func (q *singleQuery) query(ctx context.Context, name string, address string, newPacket func() *protocol.Packet,
+	responseType protocol.PacketType, unmarshal func(data []byte) error) (err error) {
+	q.conn, err = (&net.Dialer{}).DialContext(ctx, "udp", address)
+	if err != nil {
+		if contextError(ctx) != nil {
+			return fmt.Errorf("%s: [%s]: %w", name, address, contextError(ctx))
+		}
+
+		return fmt.Errorf("%s: [%s]: %w", name, address, newQueryError(err.Error(), kindOf(err), err))
+	}
+
+	defer q.conn.Close()
+
+	stop := watchContext(ctx, q.conn)
+	defer stop()
+
+	q.discarded = 0
+
+	// every attempt is sent with a new key, late replies to an earlier attempt are discarded
+	q.attempts, err = retry(ctx, q.options, func(timeout time.Duration) error {
+		return q.attempt(ctx, timeout, newPacket(), responseType, unmarshal)
+	})
+
+	if err != nil {
+		if contextError(ctx) != nil {
+			return fmt.Errorf("%s: [%s]: %w", name, q.conn.RemoteAddr(), contextError(ctx))
+		}
+
+		return fmt.Errorf("%s: [%s]: %w", name, q.conn.RemoteAddr(), err)
+	}
+
+	q.requestEnd = time.Now()
+
+	return nil
+}
+
+func (q *singleQuery) attempt(ctx context.Context, timeout time.Duration, packet *protocol.Packet,
+	responseType protocol.PacketType, unmarshal func(data []byte) error) error {
+	err := setDeadline(ctx, q.conn, timeout)
+	if err != nil {
+		return err
+	}
+
+	packet.Key = q.txID
+	q.txID++
+
+	data, err := packet.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("MarshalBinary failed: %w", err)
+	}
+
+	q.requestStart = time.Now()
+
+	_, err = q.conn.Write(data)
+	if err != nil {
+		return newQueryError(fmt.Sprintf("connection Write failed: %s", err), kindOf(err), err)
+	}
+
+	data, _, err = readResponse(q.conn, packet.Key, responseType, &q.discarded)
+	if err != nil {
+		return connectionError(q.options, "connection read failed", err)
+	}
+
+	return unmarshal(data)
+}
+
+// Attempts returns the number of times the last query was sent before it succeeded or gave up
+func (q *singleQuery) Attempts() int {
+	return q.attempts
+}
+
+// Discarded returns the number of packets ignored by the last query for not answering it,
+// packets discarded by a Mux are counted by Mux.Discarded instead
+func (q *singleQuery) Discarded() int {
+	return q.discarded
+}
+
+// retry calls attempt until it succeeds or the retry policy in options is exhausted,
+// returning how many attempts were made and the error from the last one.
+// partial results and a finished ctx aren't retried
+func retry(ctx context.Context, options *protocol.Options, attempt func(timeout time.Duration) error) (attempts int, err error) {
+	for attempts = 1; ; attempts++ {
+		err = attempt(attemptTimeout(options))
+		if err == nil || attempts >= maxAttempts(options) || contextError(ctx) != nil {
+			return
+		}
+
+		var partial *PartialResultError
+		if errors.As(err, &partial) {
+			return
+		}
+
+		if sleepContext(ctx, backoff(options, attempts)) != nil {
+			return
+		}
+	}
+}
+
+func maxAttempts(options *protocol.Options) int {
+	if options.Attempts < 1 {
+		return 1
+	}
+
+	return options.Attempts
+}
+
+func attemptTimeout(options *protocol.Options) time.Duration {
+	if options.AttemptTimeout <= 0 {
+		return options.Timeout
+	}
+
+	return options.AttemptTimeout
+}
+
+// backoff returns the wait after the given attempt number
+func backoff(options *protocol.Options, attempt int) time.Duration {
+	if options.Backoff <= 0 {
+		return 0
+	}
+
+	output := options.Backoff
+	for i := 1; i < attempt && output < time.Hour; i++ {
+		output *= 2
+	}
+
+	jitter := options.BackoffJitter
+	if jitter > 1 {
+		jitter = 1
+	}
+
+	if jitter > 0 {
+		output += time.Duration(float64(output) * jitter * (mathrand.Float64()*2 - 1))
+	}
+
+	return output
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return contextError(ctx)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/StarsiegePlayers/darkstar-query-go/v2/protocol"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetryTestSuite struct {
+	suite.Suite
+	Options *protocol.Options
+}
+
+func (t *RetryTestSuite) SetupTest() {
+	t.Options = &protocol.Options{
+		Timeout:  time.Second,
+		Attempts: 3,
+	}
+}
+
+func (t *RetryTestSuite) TestRetry_Succeeds() {
+	calls := 0
+
+	attempts, err := retry(context.Background(), t.Options, func(timeout time.Duration) error {
+		calls++
+		t.Assert().Equal(time.Second, timeout)
+
+		if calls < 2 {
+			return errors.New("lost")
+		}
+
+		return nil
+	})
+
+	t.Assert().Nil(err)
+	t.Assert().Equal(2, attempts)
+}
+
+func (t *RetryTestSuite) TestRetry_Exhausted() {
+	t.Options.AttemptTimeout = 100 * time.Millisecond
+
+	attempts, err := retry(context.Background(), t.Options, func(timeout time.Duration) error {
+		t.Assert().Equal(100*time.Millisecond, timeout)
+
+		return errors.New("lost")
+	})
+
+	t.Assert().EqualError(err, "lost")
+	t.Assert().Equal(3, attempts)
+}
+
+func (t *RetryTestSuite) TestRetry_Default() {
+	t.Options.Attempts = 0
+
+	attempts, err := retry(context.Background(), t.Options, func(timeout time.Duration) error {
+		return errors.New("lost")
+	})
+
+	t.Assert().NotNil(err)
+	t.Assert().Equal(1, attempts)
+}
+
+func (t *RetryTestSuite) TestRetry_Partial() {
+	attempts, err := retry(context.Background(), t.Options, func(timeout time.Duration) error {
+		return &PartialResultError{Total: 2, Missing: []int{2}}
+	})
+
+	t.Assert().NotNil(err)
+	t.Assert().Equal(1, attempts)
+}
+
+func (t *RetryTestSuite) TestRetry_Cancelled() {
+	t.Options.Backoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	attempts, err := retry(ctx, t.Options, func(timeout time.Duration) error {
+		return errors.New("lost")
+	})
+
+	t.Assert().EqualError(err, "lost")
+	t.Assert().Equal(1, attempts)
+}
+
+func (t *RetryTestSuite) TestBackoff() {
+	t.Assert().Equal(time.Duration(0), backoff(t.Options, 1))
+
+	t.Options.Backoff = 100 * time.Millisecond
+	t.Assert().Equal(100*time.Millisecond, backoff(t.Options, 1))
+	t.Assert().Equal(200*time.Millisecond, backoff(t.Options, 2))
+	t.Assert().Equal(400*time.Millisecond, backoff(t.Options, 3))
+
+	t.Options.BackoffJitter = 0.5
+
+	for i := 0; i < 100; i++ {
+		d := backoff(t.Options, 2)
+		t.Assert().GreaterOrEqual(int64(d), int64(100*time.Millisecond))
+		t.Assert().LessOrEqual(int64(d), int64(300*time.Millisecond))
+	}
+}
+
+func TestRetryTestSuite(t *testing.T) {
+	suite.Run(t, new(RetryTestSuite))
+}